@@ -3,18 +3,27 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"kcavo/pkg/cost"
 	"kcavo/pkg/kubernetes"
 	"kcavo/pkg/visualize"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var (
-	showBreakdown bool
-	sortBy        string
-	topN          int
+	showBreakdown  bool
+	sortBy         string
+	reverse        bool
+	topN           int
+	egressGBPerPod float64
+	groupBy        string
+	prorate        bool
+	costBasis      string
+	highlights     bool
+	controller     string
 )
 
 var analyzeCmd = &cobra.Command{
@@ -32,7 +41,13 @@ Examples:
   kubectl cost analyze                                    # Analyze current namespace
   kubectl cost analyze -A                                 # Analyze all namespaces
   kubectl cost analyze -n production --breakdown         # Show detailed breakdown
-  kubectl cost analyze --sort-by cost --top 10          # Top 10 most expensive`,
+  kubectl cost analyze --sort-by cost --top 10          # Top 10 most expensive
+  kubectl cost analyze --sort-by name                    # Alphabetical by pod name
+  kubectl cost analyze --group-by node                   # Workload cost per node
+  kubectl cost analyze --prorate                         # Prorate Jobs by lifetime
+  kubectl cost analyze --cost-basis limits               # Cost on limits, not requests
+  kubectl cost analyze --controller deployment/api       # Cost of a single workload
+  kubectl cost analyze -A -o jsonl                       # One JSON object per pod per line`,
 	RunE: runAnalyze,
 }
 
@@ -40,26 +55,53 @@ func init() {
 	rootCmd.AddCommand(analyzeCmd)
 
 	analyzeCmd.Flags().BoolVar(&showBreakdown, "breakdown", false, "show detailed cost breakdown")
-	analyzeCmd.Flags().StringVar(&sortBy, "sort-by", "cost", "sort by: cost, cpu, memory, gpu")
+	analyzeCmd.Flags().StringVar(&sortBy, "sort-by", "cost", "sort by: cost, cpu, memory, gpu, name, namespace")
+	analyzeCmd.Flags().BoolVar(&reverse, "reverse", false, "reverse the sort order")
 	analyzeCmd.Flags().IntVar(&topN, "top", 0, "show only top N results (0 = all)")
+	analyzeCmd.Flags().StringVar(&groupBy, "group-by", "", "aggregate costs by: node")
+	analyzeCmd.Flags().StringVar(&controller, "controller", "", "only cost pods owned by this controller (e.g. deployment/api)")
+	analyzeCmd.Flags().BoolVar(&highlights, "highlights", true, "print the most expensive namespace, node, and pod before the table")
+	analyzeCmd.Flags().StringVar(&costBasis, "cost-basis", "requests", "cost on: requests, limits, max (BestEffort pods cost $0 under all)")
+	analyzeCmd.Flags().BoolVar(&prorate, "prorate", false, "also show cost prorated by observed lifetime for short-lived pods (Jobs, <24h old)")
+	analyzeCmd.Flags().Float64Var(&egressGBPerPod, "egress-gb-per-pod", 0, "assumed monthly egress per pod in GB, used for the network cost estimate")
 }
 
 func runAnalyze(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	if groupBy != "" && groupBy != "node" {
+		return fmt.Errorf("unsupported --group-by value %q (supported: node)", groupBy)
+	}
+
+	basis, err := cost.ParseCostBasis(costBasis)
+	if err != nil {
+		return err
+	}
+
+	var controllerRef kubernetes.ControllerRef
+	if controller != "" {
+		if allNamespaces {
+			return fmt.Errorf("--controller cannot be combined with --all-namespaces")
+		}
+		controllerRef, err = kubernetes.ParseControllerRef(controller)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Initialize Kubernetes client
-	client, err := kubernetes.NewClient()
+	client, err := newClient()
 	if err != nil {
-		return fmt.Errorf("failed to create kubernetes client: %w", err)
+		return err
 	}
 
 	ns := getNamespace()
 
-	fmt.Printf("🔍 Analyzing costs")
+	fmt.Fprintf(os.Stderr, "🔍 Analyzing costs")
 	if ns == "" {
-		fmt.Printf(" across all namespaces...\n")
+		fmt.Fprintf(os.Stderr, " across all namespaces...\n")
 	} else {
-		fmt.Printf(" in namespace: %s...\n", ns)
+		fmt.Fprintf(os.Stderr, " in namespace: %s...\n", ns)
 	}
 
 	// Get pods
@@ -68,55 +110,222 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get pods: %w", err)
 	}
 
+	// Narrow down to a single controller's pods
+	if controller != "" {
+		pods, err = filterByController(ctx, client, ns, controllerRef, pods)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get nodes
 	nodes, err := client.GetNodes(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get nodes: %w", err)
 	}
 
+	// Get services (for LoadBalancer costs, which are not attributable to
+	// a single controller)
+	var services []corev1.Service
+	if controller == "" {
+		services, err = client.GetServices(ctx, ns)
+		if err != nil {
+			if err := optionalList(err, "services", "the LoadBalancer cost estimate"); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Select pricing and warn about its assumptions before costing
+	pricing, err := newPricing(nodes)
+	if err != nil {
+		return err
+	}
+	checkCapacityMix(nodes, pricing)
+
 	// Calculate costs
-	calculator := cost.NewCalculator()
+	calculator := cost.NewCalculatorWithPricing(pricing)
+	calculator.EnableCache()
+	calculator.SetCostBasis(basis)
+	if prorate {
+		calculator.EnableProration()
+	}
 	results := calculator.CalculatePodCosts(pods, nodes)
+	network := calculator.EstimateNetworkCost(pods, services, egressGBPerPod)
+
+	if len(results) == 0 {
+		warnNoRunningPods(ns)
+		if !isMachineOutput() && groupBy == "" {
+			return nil
+		}
+	}
+
+	if highlights && !isMachineOutput() {
+		visualize.PrintHighlights(calculator.Highlights(results, nodes))
+	}
 
-	// Apply filters
-	if topN > 0 && len(results) > topN {
-		results = results[:topN]
+	// Display results (--top only limits the rows shown, never the summary)
+	var shown, total int
+	var summary summaryTotals
+	if groupBy == "node" {
+		view := nodeCostView(calculator.AggregateByNode(nodes, pods, results), results, sortBy, reverse, topN)
+		shown, total, summary = len(view.Rows), view.Total, view.Summary
+		switch output {
+		case "json":
+			return visualize.PrintJSON(view.Rows)
+		case "yaml":
+			return visualize.PrintYAML(view.Rows)
+		case "jsonl":
+			return visualize.PrintJSONLines(view.Rows)
+		default:
+			visualize.PrintNodeCostTable(view.Rows)
+		}
+	} else {
+		view := podCostView(results, sortBy, reverse, topN)
+		shown, total, summary = len(view.Rows), view.Total, view.Summary
+		switch output {
+		case "json":
+			return visualize.PrintJSON(view.Rows)
+		case "yaml":
+			return visualize.PrintYAML(view.Rows)
+		case "jsonl":
+			return visualize.PrintJSONLines(view.Rows)
+		default:
+			visualize.PrintCostTable(view.Rows, showBreakdown)
+		}
+	}
+
+	if shown < total {
+		fmt.Printf("(showing top %d of %d)\n", shown, total)
 	}
 
-	// Display results
-	switch output {
-	case "json":
-		return visualize.PrintJSON(results)
-	case "yaml":
-		return visualize.PrintYAML(results)
-	default:
-		visualize.PrintCostTable(results, showBreakdown)
+	if len(results) == 0 {
+		return nil
 	}
 
-	// Print summary
 	fmt.Println()
-	printSummary(results)
+	printSummary(summary, network)
 
 	return nil
 }
 
-func printSummary(results []cost.PodCost) {
-	var totalCost, totalCPU, totalMemory float64
-	var totalGPU int
+// filterByController returns the pods owned (via ReplicaSets if needed) by
+// ref, erroring if the controller doesn't exist or has no running pods
+func filterByController(ctx context.Context, client *kubernetes.Client, ns string, ref kubernetes.ControllerRef, pods []corev1.Pod) ([]corev1.Pod, error) {
+	if err := client.CheckController(ctx, ns, ref); err != nil {
+		return nil, err
+	}
+
+	replicaSets, err := client.GetReplicaSets(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replicasets: %w", err)
+	}
+
+	owned := kubernetes.FilterPodsByController(pods, replicaSets, ref)
+	if !hasRunningPods(owned) {
+		return nil, fmt.Errorf("%s has no running pods in namespace %s", ref, ns)
+	}
+
+	return owned, nil
+}
+
+// limitRows returns the first n rows, or all rows when n <= 0
+func limitRows[T any](rows []T, n int) []T {
+	if n > 0 && len(rows) > n {
+		return rows[:n]
+	}
+	return rows
+}
+
+// costView is what analyze displays: the sorted rows kept by --top, the
+// number of rows before truncation, and totals over every pod
+type costView[T any] struct {
+	Rows    []T
+	Total   int
+	Summary summaryTotals
+}
 
+// podCostView sorts pod costs, keeps the top rows and totals all of them
+func podCostView(results []cost.PodCost, key string, reverse bool, top int) costView[cost.PodCost] {
+	if used, ok := visualize.SortPodCosts(results, key, reverse); !ok {
+		warnSortFallback(key, used, "pod costs")
+	}
+	return costView[cost.PodCost]{
+		Rows:    limitRows(results, top),
+		Total:   len(results),
+		Summary: summarize(results),
+	}
+}
+
+// nodeCostView sorts per-node costs and keeps the top rows; the summary
+// still covers every pod in results
+func nodeCostView(nodeCosts []cost.NodeCost, results []cost.PodCost, key string, reverse bool, top int) costView[cost.NodeCost] {
+	if used, ok := visualize.SortNodeCosts(nodeCosts, key, reverse); !ok {
+		warnSortFallback(key, used, "node costs")
+	}
+	return costView[cost.NodeCost]{
+		Rows:    limitRows(nodeCosts, top),
+		Total:   len(nodeCosts),
+		Summary: summarize(results),
+	}
+}
+
+// summaryTotals holds the figures printed in the analyze summary
+type summaryTotals struct {
+	Pods       int
+	Cost       float64
+	CPU        float64
+	Memory     float64
+	GPUs       float64
+	Prorated   float64
+	ShortLived int
+	Windows    float64
+}
+
+// summarize totals pod costs; it must be given the full result set, not the
+// rows kept by --top
+func summarize(results []cost.PodCost) summaryTotals {
+	t := summaryTotals{Pods: len(results)}
 	for _, r := range results {
-		totalCost += r.TotalCost
-		totalCPU += r.CPUCost
-		totalMemory += r.MemoryCost
-		totalGPU += r.GPUCount
+		t.Cost += r.TotalCost
+		t.Prorated += r.ProratedCost
+		if r.ShortLived {
+			t.ShortLived++
+		}
+		if r.OS == cost.OSWindows {
+			t.Windows += r.TotalCost
+		}
+		t.CPU += r.CPUCost
+		t.Memory += r.MemoryCost
+		t.GPUs += r.GPUCount
 	}
+	return t
+}
 
+func printSummary(t summaryTotals, network cost.NetworkCost) {
 	fmt.Println("📊 Summary:")
-	fmt.Printf("   Total Monthly Cost: $%.2f\n", totalCost)
-	fmt.Printf("   Total Pods: %d\n", len(results))
-	if totalGPU > 0 {
-		fmt.Printf("   Total GPUs: %d\n", totalGPU)
+	fmt.Printf("   Total Monthly Cost: $%.2f\n", t.Cost)
+	if t.Prorated > 0 {
+		fmt.Printf("   Prorated Actual Cost: $%.2f (%d short-lived pods prorated by lifetime)\n", t.Prorated, t.ShortLived)
+	}
+	fmt.Printf("   Total Pods: %d\n", t.Pods)
+	if t.GPUs > 0 {
+		fmt.Printf("   Total GPUs: %g\n", t.GPUs)
+	}
+	fmt.Printf("   CPU Cost: $%.2f (%.1f%%)\n", t.CPU, (t.CPU/t.Cost)*100)
+	fmt.Printf("   Memory Cost: $%.2f (%.1f%%)\n", t.Memory, (t.Memory/t.Cost)*100)
+	if t.Windows > 0 {
+		fmt.Printf("   Linux Cost: $%.2f, Windows Cost: $%.2f (incl. licensing premium)\n", t.Cost-t.Windows, t.Windows)
+	}
+
+	if network.TotalCost > 0 {
+		fmt.Println()
+		fmt.Println("🌐 Network (estimate, not included in total):")
+		if network.LoadBalancers > 0 {
+			fmt.Printf("   LoadBalancers: %d × flat rate = $%.2f/month\n", network.LoadBalancers, network.LoadBalancerCost)
+		}
+		if network.EgressGB > 0 {
+			fmt.Printf("   Egress: ~%.0f GB assumed = $%.2f/month\n", network.EgressGB, network.EgressCost)
+		}
 	}
-	fmt.Printf("   CPU Cost: $%.2f (%.1f%%)\n", totalCPU, (totalCPU/totalCost)*100)
-	fmt.Printf("   Memory Cost: $%.2f (%.1f%%)\n", totalMemory, (totalMemory/totalCost)*100)
 }
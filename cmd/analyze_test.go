@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"kcavo/pkg/cost"
+	"kcavo/pkg/visualize"
+)
+
+func testPodCosts() []cost.PodCost {
+	var results []cost.PodCost
+	for i := 1; i <= 5; i++ {
+		results = append(results, cost.PodCost{
+			Name:       fmt.Sprintf("pod-%d", i),
+			Namespace:  "default",
+			Node:       fmt.Sprintf("node-%d", i%2),
+			CPUCost:    float64(i),
+			MemoryCost: float64(i) / 2,
+			TotalCost:  float64(i) * 1.5,
+			GPUCount:   0.5,
+		})
+	}
+	return results
+}
+
+func TestPodCostViewSummaryIgnoresTop(t *testing.T) {
+	want := summaryTotals{Pods: 5, Cost: 22.5, CPU: 15, Memory: 7.5, GPUs: 2.5}
+
+	for _, top := range []int{0, 1, 3, 5, 10} {
+		t.Run(fmt.Sprintf("top=%d", top), func(t *testing.T) {
+			view := podCostView(testPodCosts(), visualize.SortByCost, false, top)
+
+			wantRows := 5
+			if top > 0 && top < 5 {
+				wantRows = top
+			}
+			if len(view.Rows) != wantRows || view.Total != 5 {
+				t.Errorf("showing %d of %d rows, want %d of 5", len(view.Rows), view.Total, wantRows)
+			}
+			if view.Summary != want {
+				t.Errorf("summary = %+v, want %+v", view.Summary, want)
+			}
+		})
+	}
+}
+
+func TestNodeCostViewSummaryIgnoresTop(t *testing.T) {
+	want := summaryTotals{Pods: 5, Cost: 22.5, CPU: 15, Memory: 7.5, GPUs: 2.5}
+
+	for _, top := range []int{0, 1, 2} {
+		t.Run(fmt.Sprintf("top=%d", top), func(t *testing.T) {
+			results := testPodCosts()
+			nodeCosts := cost.NewCalculator().AggregateByNode(nil, nil, results)
+			view := nodeCostView(nodeCosts, results, visualize.SortByCost, false, top)
+
+			if top == 1 && len(view.Rows) != 1 {
+				t.Errorf("showing %d node rows, want 1", len(view.Rows))
+			}
+			if view.Summary != want {
+				t.Errorf("summary = %+v, want %+v", view.Summary, want)
+			}
+		})
+	}
+}
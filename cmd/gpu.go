@@ -3,14 +3,19 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"kcavo/pkg/gpu"
 	"kcavo/pkg/kubernetes"
 	"kcavo/pkg/visualize"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
+var gpuSortBy string
+
 var gpuCmd = &cobra.Command{
 	Use:   "gpu",
 	Short: "Analyze GPU resource usage and scheduling",
@@ -24,25 +29,36 @@ Provides insights on:
 
 Examples:
   kubectl cost gpu                    # Analyze GPU usage
-  kubectl cost gpu -A                 # All namespaces`,
+  kubectl cost gpu -A                 # All namespaces
+  kubectl cost gpu -o json            # Typed recommendations for automation
+  kubectl cost gpu --sort-by name     # Alphabetical instead of by GPU count`,
 	RunE: runGPU,
 }
 
 func init() {
 	rootCmd.AddCommand(gpuCmd)
+
+	gpuCmd.Flags().StringVar(&gpuSortBy, "sort-by", "gpu", "sort by: gpu, name, namespace")
+	gpuCmd.Flags().BoolVar(&reverse, "reverse", false, "reverse the sort order")
+	addGPUThresholdFlags(gpuCmd)
 }
 
 func runGPU(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	client, err := kubernetes.NewClient()
+	thresholds, err := gpuThresholds(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
 	if err != nil {
-		return fmt.Errorf("failed to create kubernetes client: %w", err)
+		return err
 	}
 
 	ns := getNamespace()
 
-	fmt.Printf("🎮 Analyzing GPU resources...\n\n")
+	fmt.Fprintf(os.Stderr, "🎮 Analyzing GPU resources...\n\n")
 
 	// Get nodes with GPUs
 	nodes, err := client.GetNodes(ctx)
@@ -50,21 +66,85 @@ func runGPU(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get nodes: %w", err)
 	}
 
-	// Get pods
-	pods, err := client.GetPods(ctx, ns)
+	// Get pods from every namespace: GPUs used by other namespaces aren't free
+	pods, err := getClusterPods(ctx, client, ns)
 	if err != nil {
-		return fmt.Errorf("failed to get pods: %w", err)
+		return err
 	}
 
-	// Analyze GPU usage
-	analyzer := gpu.NewAnalyzer()
+	// Analyze GPU usage, then show only the requested namespace's pods
+	analyzer := gpu.NewAnalyzerWithThresholds(thresholds)
 	analysis := analyzer.Analyze(nodes, pods)
+	analysis.FilterNamespace(ns)
+	if used, ok := visualize.SortGPUAnalysis(&analysis, gpuSortBy, reverse); !ok {
+		warnSortFallback(gpuSortBy, used, "GPU tables")
+	}
 
 	// Display results
+	switch output {
+	case "json":
+		return visualize.PrintJSON(analysis)
+	case "yaml":
+		return visualize.PrintYAML(analysis)
+	case "jsonl":
+		return visualize.PrintJSONLines([]gpu.Analysis{analysis})
+	}
+
+	printGPUWarnings(analysis)
 	visualize.PrintGPUTable(analysis)
 
 	// Print recommendations
 	fmt.Println()
+	printGPURecommendations(analysis)
+
+	return nil
+}
+
+// getClusterPods lists pods in all namespaces for GPU reconciliation. If the
+// user may only list pods in ns, it falls back to those with a warning.
+func getClusterPods(ctx context.Context, client *kubernetes.Client, ns string) ([]corev1.Pod, error) {
+	pods, err := client.GetPods(ctx, "")
+	if err == nil {
+		return pods, nil
+	}
+	if ns == "" || !apierrors.IsForbidden(err) {
+		return nil, fmt.Errorf("failed to get pods: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "⚠️  Not allowed to list pods in all namespaces; GPU availability only accounts for pods in %s\n", ns)
+	pods, err = client.GetPods(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pods: %w", err)
+	}
+	return pods, nil
+}
+
+// podsInNamespace returns the pods in ns (all pods if ns is empty)
+func podsInNamespace(pods []corev1.Pod, ns string) []corev1.Pod {
+	if ns == "" {
+		return pods
+	}
+
+	filtered := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Namespace == ns {
+			filtered = append(filtered, pod)
+		}
+	}
+	return filtered
+}
+
+// printGPUWarnings reports GPU data inconsistencies on stderr
+func printGPUWarnings(analysis gpu.Analysis) {
+	for _, warning := range analysis.Warnings {
+		fmt.Fprintf(os.Stderr, "⚠️  GPU data warning: %s\n", warning)
+	}
+	if len(analysis.Warnings) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+func printGPURecommendations(analysis gpu.Analysis) {
 	fmt.Println("💡 Recommendations:")
 	for i, rec := range analysis.Recommendations {
 		fmt.Printf("   %d. %s\n", i+1, rec)
@@ -73,6 +153,4 @@ func runGPU(cmd *cobra.Command, args []string) error {
 	if len(analysis.Recommendations) == 0 {
 		fmt.Println("   No GPU optimization recommendations at this time.")
 	}
-
-	return nil
 }
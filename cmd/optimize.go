@@ -3,10 +3,12 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
 
 	"kcavo/pkg/cost"
-	"kcavo/pkg/kubernetes"
 	"kcavo/pkg/optimize"
+	"kcavo/pkg/visualize"
 
 	"github.com/spf13/cobra"
 )
@@ -18,32 +20,41 @@ var optimizeCmd = &cobra.Command{
 	
 Recommendations include:
   • Rightsizing pods (over-provisioned resources)
-  • Unused resources
+  • Unused resources (including orphaned PersistentVolumes)
   • GPU optimization
   • Spot instance opportunities
   • Resource quotas
 
 Examples:
   kubectl cost optimize               # Get recommendations
-  kubectl cost optimize -A            # Cluster-wide analysis`,
+  kubectl cost optimize -A            # Cluster-wide analysis
+  kubectl cost optimize -o json       # Savings by category for dashboards
+  kubectl cost optimize --threshold-utilization 90   # Less sensitive node check`,
 	RunE: runOptimize,
 }
 
 func init() {
 	rootCmd.AddCommand(optimizeCmd)
+
+	addOptimizerThresholdFlags(optimizeCmd)
 }
 
 func runOptimize(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	client, err := kubernetes.NewClient()
+	thresholds, err := optimizerThresholds(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
 	if err != nil {
-		return fmt.Errorf("failed to create kubernetes client: %w", err)
+		return err
 	}
 
 	ns := getNamespace()
 
-	fmt.Printf("💰 Analyzing cluster for cost optimization opportunities...\n\n")
+	fmt.Fprintf(os.Stderr, "💰 Analyzing cluster for cost optimization opportunities...\n\n")
 
 	// Get resources
 	pods, err := client.GetPods(ctx, ns)
@@ -56,36 +67,93 @@ func runOptimize(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get nodes: %w", err)
 	}
 
+	limitRanges, err := client.GetLimitRanges(ctx, ns)
+	if err != nil {
+		if err := optionalList(err, "limit ranges", "the LimitRange defaults check"); err != nil {
+			return err
+		}
+	}
+
+	pvs, err := client.GetPVs(ctx)
+	if err != nil {
+		if err := optionalList(err, "persistent volumes", "the orphaned volume check"); err != nil {
+			return err
+		}
+	}
+
+	// Select pricing and warn about its assumptions before costing
+	pricing, err := newPricing(nodes)
+	if err != nil {
+		return err
+	}
+	checkCapacityMix(nodes, pricing)
+
 	// Calculate current costs
-	calculator := cost.NewCalculator()
+	calculator := cost.NewCalculatorWithPricing(pricing)
+	calculator.EnableCache()
 	costs := calculator.CalculatePodCosts(pods, nodes)
+	if len(costs) == 0 {
+		warnNoRunningPods(ns)
+	}
 
 	// Get optimization recommendations
-	optimizer := optimize.NewOptimizer()
-	recommendations := optimizer.Analyze(pods, nodes, costs)
+	optimizer := optimize.NewOptimizerWithThresholds(pricing, thresholds)
+	recommendations := optimizer.Analyze(pods, nodes, costs, limitRanges, pvs)
+
+	report := optimize.NewReport(recommendations)
 
 	// Display recommendations
+	switch output {
+	case "json":
+		return visualize.PrintJSON(report)
+	case "yaml":
+		return visualize.PrintYAML(report)
+	case "jsonl":
+		return visualize.PrintJSONLines([]optimize.Report{report})
+	default:
+		printRecommendations(report, costs)
+	}
+
+	return nil
+}
+
+func printRecommendations(report optimize.Report, costs []cost.PodCost) {
 	fmt.Println("📋 Optimization Recommendations:")
 	fmt.Println()
 
-	totalSavings := 0.0
-	for i, rec := range recommendations {
+	for i, rec := range report.Recommendations {
 		fmt.Printf("   %d. %s\n", i+1, rec.Title)
 		fmt.Printf("      💡 %s\n", rec.Description)
 		fmt.Printf("      💵 Potential savings: $%.2f/month\n", rec.Savings)
 		fmt.Printf("      🎯 Priority: %s\n", rec.Priority)
 		fmt.Println()
-		totalSavings += rec.Savings
 	}
 
-	if len(recommendations) == 0 {
+	if len(report.Recommendations) == 0 {
 		fmt.Println("   ✅ No optimization opportunities found. Your cluster is well-optimized!")
-	} else {
-		fmt.Printf("💰 Total Potential Savings: $%.2f/month (%.1f%% reduction)\n",
-			totalSavings, calculateSavingsPercentage(costs, totalSavings))
+		return
 	}
 
-	return nil
+	fmt.Printf("💰 Total Potential Savings: $%.2f/month (%.1f%% reduction)\n",
+		report.TotalSavings, calculateSavingsPercentage(costs, report.TotalSavings))
+
+	// Categories ordered by savings (highest first)
+	categories := make([]string, 0, len(report.SavingsByCategory))
+	for category := range report.SavingsByCategory {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		si, sj := report.SavingsByCategory[categories[i]], report.SavingsByCategory[categories[j]]
+		if si != sj {
+			return si > sj
+		}
+		return categories[i] < categories[j]
+	})
+
+	fmt.Println("   By category:")
+	for _, category := range categories {
+		fmt.Printf("   • %s: $%.2f/month\n", category, report.SavingsByCategory[category])
+	}
 }
 
 func calculateSavingsPercentage(costs []cost.PodCost, savings float64) float64 {
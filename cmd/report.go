@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"kcavo/pkg/cost"
+	"kcavo/pkg/gpu"
+	"kcavo/pkg/optimize"
+	"kcavo/pkg/visualize"
+
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a combined cost, GPU, and optimization report",
+	Long: `Run cost analysis, GPU analysis, and optimization in one pass.
+
+Cluster resources are listed once and shared across all three analyzers,
+which keeps the report cheap on large clusters. With -o json or -o yaml a
+single document with cost, network, gpu, and optimize sections is emitted.
+
+Examples:
+  kubectl cost report                 # Human-readable weekly digest
+  kubectl cost report -A -o json      # Cluster-wide machine-readable report`,
+	RunE: runReport,
+}
+
+// combinedReport is the machine-readable document produced by report
+type combinedReport struct {
+	Cost     []cost.PodCost   `json:"cost" yaml:"cost"`
+	Network  cost.NetworkCost `json:"network" yaml:"network"`
+	GPU      gpu.Analysis     `json:"gpu" yaml:"gpu"`
+	Optimize optimize.Report  `json:"optimize" yaml:"optimize"`
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().BoolVar(&showBreakdown, "breakdown", false, "show detailed cost breakdown")
+	reportCmd.Flags().StringVar(&costBasis, "cost-basis", "requests", "cost on: requests, limits, max (BestEffort pods cost $0 under all)")
+	reportCmd.Flags().BoolVar(&prorate, "prorate", false, "also show cost prorated by observed lifetime for short-lived pods (Jobs, <24h old)")
+	reportCmd.Flags().Float64Var(&egressGBPerPod, "egress-gb-per-pod", 0, "assumed monthly egress per pod in GB, used for the network cost estimate")
+	addOptimizerThresholdFlags(reportCmd)
+	addGPUThresholdFlags(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	basis, err := cost.ParseCostBasis(costBasis)
+	if err != nil {
+		return err
+	}
+
+	optimizerSettings, err := optimizerThresholds(cmd)
+	if err != nil {
+		return err
+	}
+
+	gpuSettings, err := gpuThresholds(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ns := getNamespace()
+
+	fmt.Fprintf(os.Stderr, "📑 Generating cost report")
+	if ns == "" {
+		fmt.Fprintf(os.Stderr, " across all namespaces...\n\n")
+	} else {
+		fmt.Fprintf(os.Stderr, " in namespace: %s...\n\n", ns)
+	}
+
+	// Fetch everything once and share it across the analyzers. GPU
+	// reconciliation needs every namespace's pods; the rest use ns only.
+	clusterPods, err := getClusterPods(ctx, client, ns)
+	if err != nil {
+		return err
+	}
+	pods := podsInNamespace(clusterPods, ns)
+
+	nodes, err := client.GetNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	services, err := client.GetServices(ctx, ns)
+	if err != nil {
+		if err := optionalList(err, "services", "the LoadBalancer cost estimate"); err != nil {
+			return err
+		}
+	}
+
+	limitRanges, err := client.GetLimitRanges(ctx, ns)
+	if err != nil {
+		if err := optionalList(err, "limit ranges", "the LimitRange defaults check"); err != nil {
+			return err
+		}
+	}
+
+	pvs, err := client.GetPVs(ctx)
+	if err != nil {
+		if err := optionalList(err, "persistent volumes", "the orphaned volume check"); err != nil {
+			return err
+		}
+	}
+
+	pricing, err := newPricing(nodes)
+	if err != nil {
+		return err
+	}
+	checkCapacityMix(nodes, pricing)
+	calculator := cost.NewCalculatorWithPricing(pricing)
+	calculator.EnableCache()
+	calculator.SetCostBasis(basis)
+	if prorate {
+		calculator.EnableProration()
+	}
+	costs := calculator.CalculatePodCosts(pods, nodes)
+	network := calculator.EstimateNetworkCost(pods, services, egressGBPerPod)
+	if len(costs) == 0 {
+		warnNoRunningPods(ns)
+	}
+
+	analysis := gpu.NewAnalyzerWithThresholds(gpuSettings).Analyze(nodes, clusterPods)
+	analysis.FilterNamespace(ns)
+	report := optimize.NewReport(optimize.NewOptimizerWithThresholds(pricing, optimizerSettings).Analyze(pods, nodes, costs, limitRanges, pvs))
+
+	combined := combinedReport{
+		Cost:     costs,
+		Network:  network,
+		GPU:      analysis,
+		Optimize: report,
+	}
+
+	switch output {
+	case "json":
+		return visualize.PrintJSON(combined)
+	case "yaml":
+		return visualize.PrintYAML(combined)
+	case "jsonl":
+		return visualize.PrintJSONLines([]combinedReport{combined})
+	}
+
+	fmt.Println("💵 Costs:")
+	if len(costs) > 0 {
+		visualize.PrintCostTable(costs, showBreakdown)
+		fmt.Println()
+		printSummary(summarize(costs), network)
+	}
+
+	fmt.Println()
+	printGPUWarnings(analysis)
+	visualize.PrintGPUTable(analysis)
+	fmt.Println()
+	printGPURecommendations(analysis)
+
+	fmt.Println()
+	printRecommendations(report, costs)
+
+	return nil
+}
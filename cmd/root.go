@@ -1,18 +1,41 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
+
+	"kcavo/pkg/cost"
+	"kcavo/pkg/kubernetes"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 var (
 	cfgFile       string
+	kubeconfig    string
 	namespace     string
 	allNamespaces bool
 	output        string
+	spotDiscount  float64
+
+	provider          string
+	bareMetalMonthly  float64
+	bareMetalCPUCores int
+	bareMetalMemoryGB int
+
+	extendedResourcePrices map[string]string
+)
+
+// Process exit codes returned by ExitCode
+const (
+	exitError           = 1
+	exitConnectionError = 2
 )
 
 var rootCmd = &cobra.Command{
@@ -29,7 +52,8 @@ Example usage:
   kubectl cost analyze --all-namespaces   # Analyze cluster-wide costs
   kubectl cost visualize                  # Visualize resources
   kubectl cost gpu                        # Analyze GPU usage
-  kubectl cost optimize                   # Get optimization recommendations`,
+  kubectl cost optimize                   # Get optimization recommendations
+  kubectl cost report                     # Combined cost, GPU, and optimization report`,
 	Version: "1.0.0",
 }
 
@@ -37,14 +61,31 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// ExitCode maps an error returned by Execute to a process exit code
+func ExitCode(err error) int {
+	if errors.Is(err, kubernetes.ErrNoKubeConfig) {
+		return exitConnectionError
+	}
+	return exitError
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.kubectl-cost.yaml)")
+	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "path to the kubeconfig file (default is in-cluster, $KUBECONFIG, then ~/.kube/config)")
 	rootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace (default is current context namespace)")
 	rootCmd.PersistentFlags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "analyze across all namespaces")
-	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "output format: table, json, yaml")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "output format: table, json, yaml, jsonl")
+	rootCmd.PersistentFlags().StringVar(&provider, "provider", "aws", "pricing profile: aws, gcp, azure, baremetal")
+	rootCmd.PersistentFlags().Float64Var(&bareMetalMonthly, "baremetal-monthly-cost", 0, "amortized monthly hardware cost for --provider baremetal")
+	rootCmd.PersistentFlags().IntVar(&bareMetalCPUCores, "baremetal-cpu-cores", 0, "total CPU cores for --provider baremetal (0 = sum node capacity)")
+	rootCmd.PersistentFlags().IntVar(&bareMetalMemoryGB, "baremetal-memory-gb", 0, "total memory in GB for --provider baremetal (0 = sum node capacity)")
+	rootCmd.PersistentFlags().Float64Var(&spotDiscount, "spot-discount", 0, "discount applied to pods on spot nodes, as a fraction (e.g. 0.7 for 70% off)")
+	rootCmd.PersistentFlags().StringToStringVar(&extendedResourcePrices, "extended-resource-price", nil, "hourly rate per unit of an extended resource, e.g. squat.ai/fuse=0.05 (repeatable)")
+
+	cobra.CheckErr(viper.BindPFlag("provider", rootCmd.PersistentFlags().Lookup("provider")))
 }
 
 func initConfig() {
@@ -65,6 +106,16 @@ func initConfig() {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
+
+// newClient creates a Kubernetes client honoring the --kubeconfig flag
+func newClient() (*kubernetes.Client, error) {
+	client, err := kubernetes.NewClientWithKubeconfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	return client, nil
+}
+
 func getNamespace() string {
 	if allNamespaces {
 		return ""
@@ -74,3 +125,139 @@ func getNamespace() string {
 	}
 	return "default"
 }
+
+// newPricing returns the pricing selected by the global flags (or the
+// provider set in the config file). Nodes are used to size bare-metal
+// capacity when it isn't given explicitly.
+func newPricing(nodes []corev1.Node) (*cost.Pricing, error) {
+	if spotDiscount < 0 || spotDiscount >= 1 {
+		return nil, fmt.Errorf("--spot-discount must be a fraction in [0, 1), got %g", spotDiscount)
+	}
+
+	var pricing *cost.Pricing
+	switch p := viper.GetString("provider"); p {
+	case "", "aws":
+		pricing = cost.DefaultPricing()
+	case "gcp":
+		pricing = cost.GCPPricing()
+	case "azure":
+		pricing = cost.AzurePricing()
+	case "baremetal":
+		if bareMetalMonthly <= 0 {
+			return nil, fmt.Errorf("--provider baremetal requires --baremetal-monthly-cost")
+		}
+		cores, memGB := bareMetalCPUCores, bareMetalMemoryGB
+		if cores == 0 || memGB == 0 {
+			nodeCores, nodeMemGB := sumNodeCapacity(nodes)
+			if cores == 0 {
+				cores = nodeCores
+			}
+			if memGB == 0 {
+				memGB = nodeMemGB
+			}
+		}
+		if cores <= 0 || memGB <= 0 {
+			return nil, fmt.Errorf("could not determine cluster capacity for baremetal pricing; set --baremetal-cpu-cores and --baremetal-memory-gb")
+		}
+		pricing = cost.BareMetalPricing(cores, memGB, bareMetalMonthly)
+	default:
+		return nil, fmt.Errorf("unsupported provider %q (supported: aws, gcp, azure, baremetal)", p)
+	}
+
+	extended, err := extendedResourcePricing()
+	if err != nil {
+		return nil, err
+	}
+
+	pricing.SpotDiscount = spotDiscount
+	pricing.ExtendedResourcePricing = extended
+	return pricing, nil
+}
+
+// extendedResourcePricing returns hourly extended resource rates from the
+// pricing.extended_resources config map, overridden per resource by
+// --extended-resource-price
+func extendedResourcePricing() (map[corev1.ResourceName]float64, error) {
+	rates := viper.GetStringMapString("pricing.extended_resources")
+	for name, rate := range extendedResourcePrices {
+		rates[name] = rate
+	}
+	if len(rates) == 0 {
+		return nil, nil
+	}
+
+	pricing := make(map[corev1.ResourceName]float64, len(rates))
+	for name, rate := range rates {
+		value, err := strconv.ParseFloat(rate, 64)
+		if err != nil || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, fmt.Errorf("invalid hourly rate %q for extended resource %s", rate, name)
+		}
+		pricing[corev1.ResourceName(name)] = value
+	}
+	return pricing, nil
+}
+
+// sumNodeCapacity totals CPU cores and memory (GB) across nodes
+func sumNodeCapacity(nodes []corev1.Node) (int, int) {
+	var cores, memBytes float64
+	for _, node := range nodes {
+		cpu := node.Status.Capacity[corev1.ResourceCPU]
+		mem := node.Status.Capacity[corev1.ResourceMemory]
+		cores += cpu.AsApproximateFloat64()
+		memBytes += mem.AsApproximateFloat64()
+	}
+	return int(math.Round(cores)), int(math.Round(memBytes / (1024 * 1024 * 1024)))
+}
+
+// checkCapacityMix warns when spot and on-demand nodes are mixed but every
+// node is priced the same, which overstates the cost of spot workloads
+func checkCapacityMix(nodes []corev1.Node, pricing *cost.Pricing) {
+	mix := cost.DetectCapacityMix(nodes)
+	if !mix.Mixed() || pricing.SpotDiscount > 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  Cluster mixes %d spot and %d on-demand nodes but no spot discount is configured; costs for spot workloads may be overstated. Consider --spot-discount.\n\n",
+		mix.Spot, mix.OnDemand)
+}
+
+// isMachineOutput reports whether the selected output format is meant for
+// machine consumers rather than a human-readable table
+func isMachineOutput() bool {
+	return output == "json" || output == "yaml" || output == "jsonl"
+}
+
+// warnNoRunningPods tells the user why a result set is empty. It goes to
+// stderr so JSON/YAML on stdout stays valid for machine consumers.
+func warnNoRunningPods(ns string) {
+	if ns == "" {
+		fmt.Fprintln(os.Stderr, "ℹ️  No running pods found across all namespaces (are they Pending?)")
+		return
+	}
+	fmt.Fprintf(os.Stderr, "ℹ️  No running pods found in namespace %s (are they Pending or in another namespace?)\n", ns)
+}
+
+// optionalList handles an error from listing a resource that only feeds a
+// secondary check. RBAC denials are reported on stderr and the check is
+// skipped (nil is returned); any other error is returned wrapped.
+func optionalList(err error, resource, skipped string) error {
+	if apierrors.IsForbidden(err) {
+		fmt.Fprintf(os.Stderr, "⚠️  Not allowed to list %s; skipping %s\n", resource, skipped)
+		return nil
+	}
+	return fmt.Errorf("failed to get %s: %w", resource, err)
+}
+
+// warnSortFallback tells the user a --sort-by key didn't apply to a view
+func warnSortFallback(requested, used, view string) {
+	fmt.Fprintf(os.Stderr, "⚠️  --sort-by %s is not supported for %s; sorting by %s instead\n", requested, view, used)
+}
+
+// hasRunningPods reports whether any pod is in the Running phase
+func hasRunningPods(pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			return true
+		}
+	}
+	return false
+}
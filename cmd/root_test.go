@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"kcavo/pkg/kubernetes"
+
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestOptionalList(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "persistentvolumes"}, "", errors.New("rbac"))
+	if err := optionalList(forbidden, "persistent volumes", "the orphaned volume check"); err != nil {
+		t.Errorf("forbidden list returned %v, want nil", err)
+	}
+
+	other := errors.New("connection refused")
+	err := optionalList(other, "persistent volumes", "the orphaned volume check")
+	if !errors.Is(err, other) {
+		t.Errorf("optionalList(%v) = %v, want it wrapped", other, err)
+	}
+}
+
+func TestMissingKubeconfigExitCode(t *testing.T) {
+	// Keep rest.InClusterConfig from succeeding when run inside a pod
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+	_, err := kubernetes.NewClientWithKubeconfig("")
+	if !errors.Is(err, kubernetes.ErrNoKubeConfig) {
+		t.Fatalf("NewClientWithKubeconfig error = %v, want ErrNoKubeConfig", err)
+	}
+	if code := ExitCode(err); code != 2 {
+		t.Errorf("ExitCode = %d, want 2", code)
+	}
+}
+
+func TestExtendedResourcePricing(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(config, []byte("pricing:\n  extended_resources:\n    squat.ai/fuse: 0.05\n    example.com/fpga: 1.2\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile(config)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(viper.Reset)
+
+	extendedResourcePrices = map[string]string{"example.com/fpga": "2.5", "example.com/Tpu": "3"}
+	t.Cleanup(func() { extendedResourcePrices = nil })
+
+	got, err := extendedResourcePricing()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[corev1.ResourceName]float64{
+		"squat.ai/fuse":    0.05, // from config
+		"example.com/fpga": 2.5,  // flag overrides config
+		"example.com/Tpu":  3,    // flag keeps its case
+	}
+	if len(got) != len(want) {
+		t.Fatalf("extendedResourcePricing() = %v, want %v", got, want)
+	}
+	for name, rate := range want {
+		if got[name] != rate {
+			t.Errorf("rate for %s = %g, want %g", name, got[name], rate)
+		}
+	}
+
+	for _, bad := range []string{"abc", "-1", "NaN"} {
+		extendedResourcePrices = map[string]string{"squat.ai/fuse": bad}
+		if _, err := extendedResourcePricing(); err == nil {
+			t.Errorf("rate %q: expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"kcavo/pkg/gpu"
+	"kcavo/pkg/optimize"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	thresholdUtilization float64
+	nodeSavingsPct       float64
+	gpuLowUtilization    float64
+	gpuHighUtilization   float64
+)
+
+func addOptimizerThresholdFlags(c *cobra.Command) {
+	defaults := optimize.DefaultThresholds()
+	c.Flags().Float64Var(&thresholdUtilization, "threshold-utilization", defaults.UnderutilizedPct, "flag nodes whose allocatable CPU exceeds this % of capacity as underutilized")
+	c.Flags().Float64Var(&nodeSavingsPct, "node-savings-pct", defaults.NodeSavingsPct, "% of an underutilized node's cost assumed recoverable")
+}
+
+func addGPUThresholdFlags(c *cobra.Command) {
+	defaults := gpu.DefaultThresholds()
+	c.Flags().Float64Var(&gpuLowUtilization, "gpu-low-utilization", defaults.LowUtilizationPct, "recommend scaling down below this GPU utilization %")
+	c.Flags().Float64Var(&gpuHighUtilization, "gpu-high-utilization", defaults.HighUtilizationPct, "recommend adding GPU capacity above this utilization %")
+}
+
+// optimizerThresholds returns the optimizer thresholds from flags, falling
+// back to the thresholds section of the config file
+func optimizerThresholds(c *cobra.Command) (optimize.Thresholds, error) {
+	thresholds := optimize.Thresholds{
+		UnderutilizedPct: configFloat(c, "threshold-utilization", "thresholds.utilization", thresholdUtilization),
+		NodeSavingsPct:   configFloat(c, "node-savings-pct", "thresholds.node_savings", nodeSavingsPct),
+	}
+	return thresholds, thresholds.Validate()
+}
+
+// gpuThresholds returns the GPU analyzer thresholds from flags, falling
+// back to the thresholds section of the config file
+func gpuThresholds(c *cobra.Command) (gpu.Thresholds, error) {
+	thresholds := gpu.Thresholds{
+		LowUtilizationPct:  configFloat(c, "gpu-low-utilization", "thresholds.gpu_low_utilization", gpuLowUtilization),
+		HighUtilizationPct: configFloat(c, "gpu-high-utilization", "thresholds.gpu_high_utilization", gpuHighUtilization),
+	}
+	return thresholds, thresholds.Validate()
+}
+
+// configFloat prefers an explicitly set flag, then the config file, then
+// the flag default
+func configFloat(c *cobra.Command, flag, key string, value float64) float64 {
+	if !c.Flags().Changed(flag) && viper.IsSet(key) {
+		return viper.GetFloat64(key)
+	}
+	return value
+}
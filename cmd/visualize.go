@@ -3,15 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
-	"kcavo/pkg/kubernetes"
 	"kcavo/pkg/visualize"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	resourceType string
+	resourceType    string
+	visualizeSortBy string
 )
 
 var visualizeCmd = &cobra.Command{
@@ -29,7 +30,8 @@ Supported resource types:
 Examples:
   kubectl cost visualize                     # Visualize all resources
   kubectl cost visualize --type pods         # Show only pods
-  kubectl cost visualize -A                  # All namespaces`,
+  kubectl cost visualize -A                  # All namespaces
+  kubectl cost visualize --sort-by memory    # Largest nodes and pods first`,
 	RunE: runVisualize,
 }
 
@@ -37,23 +39,25 @@ func init() {
 	rootCmd.AddCommand(visualizeCmd)
 
 	visualizeCmd.Flags().StringVar(&resourceType, "type", "all", "resource type to visualize")
+	visualizeCmd.Flags().StringVar(&visualizeSortBy, "sort-by", "name", "sort nodes and pods by: name, namespace, cpu, memory")
+	visualizeCmd.Flags().BoolVar(&reverse, "reverse", false, "reverse the sort order")
 }
 
 func runVisualize(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	client, err := kubernetes.NewClient()
+	client, err := newClient()
 	if err != nil {
-		return fmt.Errorf("failed to create kubernetes client: %w", err)
+		return err
 	}
 
 	ns := getNamespace()
 
-	fmt.Printf("📦 Visualizing resources")
+	fmt.Fprintf(os.Stderr, "📦 Visualizing resources")
 	if ns == "" {
-		fmt.Printf(" across all namespaces...\n\n")
+		fmt.Fprintf(os.Stderr, " across all namespaces...\n\n")
 	} else {
-		fmt.Printf(" in namespace: %s...\n\n", ns)
+		fmt.Fprintf(os.Stderr, " in namespace: %s...\n\n", ns)
 	}
 
 	if resourceType == "all" || resourceType == "nodes" {
@@ -61,6 +65,9 @@ func runVisualize(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to get nodes: %w", err)
 		}
+		if used, ok := visualize.SortNodes(nodes, visualizeSortBy, reverse); !ok {
+			warnSortFallback(visualizeSortBy, used, "nodes")
+		}
 		visualize.PrintNodeTable(nodes)
 		fmt.Println()
 	}
@@ -70,8 +77,16 @@ func runVisualize(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to get pods: %w", err)
 		}
-		visualize.PrintPodTable(pods)
-		fmt.Println()
+		if !hasRunningPods(pods) {
+			warnNoRunningPods(ns)
+		}
+		if len(pods) > 0 {
+			if used, ok := visualize.SortPods(pods, visualizeSortBy, reverse); !ok {
+				warnSortFallback(visualizeSortBy, used, "pods")
+			}
+			visualize.PrintPodTable(pods)
+			fmt.Println()
+		}
 	}
 
 	return nil
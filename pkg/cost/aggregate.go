@@ -0,0 +1,71 @@
+package cost
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// UnscheduledNode is the group name used for pods without a node
+const UnscheduledNode = "<unscheduled>"
+
+// NodeCost represents workload cost attributed to a single node
+type NodeCost struct {
+	Node              string
+	PodCount          int
+	WorkloadCost      float64 // Sum of pod costs scheduled on the node
+	NodeCost          float64 // Provisioned cost of the node itself
+	PackingEfficiency float64 // WorkloadCost / NodeCost as a percentage
+}
+
+// AggregateByNode sums pod costs per node and compares them to the node's
+// provisioned cost. costs are the running pods' costs; pods that are still
+// Pending without a node are costed here and grouped under UnscheduledNode.
+func (c *Calculator) AggregateByNode(nodes []corev1.Node, pods []corev1.Pod, costs []PodCost) []NodeCost {
+	byNode := make(map[string]*NodeCost)
+
+	for _, node := range nodes {
+		byNode[node.Name] = &NodeCost{
+			Node:     node.Name,
+			NodeCost: c.CalculateNodeCost(node),
+		}
+	}
+
+	add := func(name string, total float64) {
+		nc, ok := byNode[name]
+		if !ok {
+			nc = &NodeCost{Node: name}
+			byNode[name] = nc
+		}
+		nc.PodCount++
+		nc.WorkloadCost += total
+	}
+
+	for _, pc := range costs {
+		add(pc.Node, pc.TotalCost)
+	}
+
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" && pod.Status.Phase == corev1.PodPending {
+			add(UnscheduledNode, c.cachedPodCost(pod).TotalCost)
+		}
+	}
+
+	results := make([]NodeCost, 0, len(byNode))
+	for _, nc := range byNode {
+		if nc.NodeCost > 0 {
+			nc.PackingEfficiency = (nc.WorkloadCost / nc.NodeCost) * 100
+		}
+		results = append(results, *nc)
+	}
+
+	// Sort by workload cost (descending)
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].WorkloadCost != results[j].WorkloadCost {
+			return results[i].WorkloadCost > results[j].WorkloadCost
+		}
+		return results[i].Node < results[j].Node
+	})
+
+	return results
+}
@@ -0,0 +1,69 @@
+package cost
+
+import (
+	"math"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func cpuContainer(cpu, memory string) corev1.Container {
+	return corev1.Container{
+		Name: "c",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func TestAggregateByNode(t *testing.T) {
+	c := NewCalculator()
+	node := labeledNode("node-1", nil) // 4 CPU, 16Gi
+	idle := labeledNode("node-2", nil)
+
+	running := []corev1.Pod{
+		testPod("a", cpuContainer("1", "4Gi")),
+		testPod("b", cpuContainer("1", "4Gi")),
+	}
+	pending := testPod("waiting", cpuContainer("2", "2Gi"))
+	pending.Spec.NodeName = ""
+	pending.Status.Phase = corev1.PodPending
+	succeeded := testPod("done", cpuContainer("1", "1Gi"))
+	succeeded.Spec.NodeName = ""
+	succeeded.Status.Phase = corev1.PodSucceeded
+
+	pods := append(append([]corev1.Pod{}, running...), pending, succeeded)
+	costs := c.CalculatePodCosts(pods, []corev1.Node{node, idle})
+
+	byName := make(map[string]NodeCost)
+	for _, nc := range c.AggregateByNode([]corev1.Node{node, idle}, pods, costs) {
+		byName[nc.Node] = nc
+	}
+
+	// Half of node-1's CPU and memory is requested, so half its cost is used
+	got := byName["node-1"]
+	nodeCost := c.CalculateNodeCost(node)
+	if got.PodCount != 2 || got.NodeCost != nodeCost {
+		t.Errorf("node-1 = %+v, want 2 pods and node cost %g", got, nodeCost)
+	}
+	if want := (got.WorkloadCost / nodeCost) * 100; got.PackingEfficiency != want || math.Abs(want-50) > 1e-9 {
+		t.Errorf("node-1 packing efficiency = %g, want 50", got.PackingEfficiency)
+	}
+
+	if idle := byName["node-2"]; idle.PodCount != 0 || idle.PackingEfficiency != 0 {
+		t.Errorf("node-2 = %+v, want no pods and 0%% efficiency", idle)
+	}
+
+	unscheduled, ok := byName[UnscheduledNode]
+	if !ok {
+		t.Fatalf("no %s group for the pending pod", UnscheduledNode)
+	}
+	want := c.calculatePodCost(pending).TotalCost
+	if unscheduled.PodCount != 1 || unscheduled.WorkloadCost != want || unscheduled.NodeCost != 0 {
+		t.Errorf("%s = %+v, want 1 pod costing %g with no node cost", UnscheduledNode, unscheduled, want)
+	}
+}
@@ -0,0 +1,66 @@
+package cost
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// CostBasis selects which container quantities CPU and memory are costed on.
+// Selection is made per container and per resource, then summed for the pod.
+// BestEffort containers (neither requests nor limits) cost nothing under
+// every basis, since there is no reserved capacity to attribute.
+type CostBasis string
+
+const (
+	// CostBasisRequests costs requests, falling back to limits when a
+	// container sets no request (the default)
+	CostBasisRequests CostBasis = "requests"
+	// CostBasisLimits costs limits (worst-case capacity), falling back to
+	// requests when a container sets no limit
+	CostBasisLimits CostBasis = "limits"
+	// CostBasisMax costs the larger of request and limit
+	CostBasisMax CostBasis = "max"
+)
+
+// ParseCostBasis validates a cost basis name
+func ParseCostBasis(s string) (CostBasis, error) {
+	switch basis := CostBasis(s); basis {
+	case CostBasisRequests, CostBasisLimits, CostBasisMax:
+		return basis, nil
+	}
+	return "", fmt.Errorf("invalid cost basis %q (supported: requests, limits, max)", s)
+}
+
+// SetCostBasis changes the quantities CPU and memory are costed on
+func (c *Calculator) SetCostBasis(basis CostBasis) {
+	c.basis = basis
+	if c.cache != nil {
+		c.cache.entries = make(map[uint64]PodCost)
+	}
+}
+
+// billableQuantity picks the container quantity to cost for a resource
+func (c *Calculator) billableQuantity(container corev1.Container, name corev1.ResourceName) resource.Quantity {
+	req := container.Resources.Requests[name]
+	lim := container.Resources.Limits[name]
+
+	switch c.basis {
+	case CostBasisLimits:
+		if lim.IsZero() {
+			return req
+		}
+		return lim
+	case CostBasisMax:
+		if lim.Cmp(req) > 0 {
+			return lim
+		}
+		return req
+	default:
+		if req.IsZero() {
+			return lim
+		}
+		return req
+	}
+}
@@ -0,0 +1,101 @@
+package cost
+
+import (
+	"math"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// basisContainer sets CPU and memory requests and limits; "" leaves one unset
+func basisContainer(cpuReq, cpuLim, memReq, memLim string) corev1.Container {
+	c := corev1.Container{Name: "c"}
+	set := func(list *corev1.ResourceList, name corev1.ResourceName, value string) {
+		if value == "" {
+			return
+		}
+		if *list == nil {
+			*list = corev1.ResourceList{}
+		}
+		(*list)[name] = resource.MustParse(value)
+	}
+	set(&c.Resources.Requests, corev1.ResourceCPU, cpuReq)
+	set(&c.Resources.Limits, corev1.ResourceCPU, cpuLim)
+	set(&c.Resources.Requests, corev1.ResourceMemory, memReq)
+	set(&c.Resources.Limits, corev1.ResourceMemory, memLim)
+	return c
+}
+
+func TestCalculatePodCostBasis(t *testing.T) {
+	const gi = 1024 * 1024 * 1024
+
+	tests := []struct {
+		name      string
+		container corev1.Container
+		basis     CostBasis
+		wantCores float64
+		wantBytes float64
+	}{
+		{"request only/requests", basisContainer("500m", "", "1Gi", ""), CostBasisRequests, 0.5, gi},
+		{"request only/limits", basisContainer("500m", "", "1Gi", ""), CostBasisLimits, 0.5, gi},
+		{"request only/max", basisContainer("500m", "", "1Gi", ""), CostBasisMax, 0.5, gi},
+
+		{"limit only/requests", basisContainer("", "2", "", "4Gi"), CostBasisRequests, 2, 4 * gi},
+		{"limit only/limits", basisContainer("", "2", "", "4Gi"), CostBasisLimits, 2, 4 * gi},
+		{"limit only/max", basisContainer("", "2", "", "4Gi"), CostBasisMax, 2, 4 * gi},
+
+		{"request < limit/requests", basisContainer("500m", "2", "1Gi", "4Gi"), CostBasisRequests, 0.5, gi},
+		{"request < limit/limits", basisContainer("500m", "2", "1Gi", "4Gi"), CostBasisLimits, 2, 4 * gi},
+		{"request < limit/max", basisContainer("500m", "2", "1Gi", "4Gi"), CostBasisMax, 2, 4 * gi},
+
+		{"best effort/requests", basisContainer("", "", "", ""), CostBasisRequests, 0, 0},
+		{"best effort/limits", basisContainer("", "", "", ""), CostBasisLimits, 0, 0},
+		{"best effort/max", basisContainer("", "", "", ""), CostBasisMax, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCalculator()
+			c.SetCostBasis(tt.basis)
+			got := c.calculatePodCost(testPod("p", tt.container))
+
+			wantCPU := c.pricing.CalculateCPUCost(tt.wantCores)
+			wantMem := c.pricing.CalculateMemoryCost(tt.wantBytes)
+			if math.Abs(got.CPUCost-wantCPU) > 1e-9 {
+				t.Errorf("CPUCost = %g, want %g (%g cores)", got.CPUCost, wantCPU, tt.wantCores)
+			}
+			if math.Abs(got.MemoryCost-wantMem) > 1e-9 {
+				t.Errorf("MemoryCost = %g, want %g (%g bytes)", got.MemoryCost, wantMem, tt.wantBytes)
+			}
+			if tt.wantCores == 0 && got.TotalCost != 0 {
+				t.Errorf("BestEffort TotalCost = %g, want 0", got.TotalCost)
+			}
+		})
+	}
+}
+
+func TestSetCostBasisResetsCache(t *testing.T) {
+	c := NewCalculator()
+	c.EnableCache()
+	pod := testPod("p", basisContainer("500m", "2", "1Gi", "4Gi"))
+
+	requests := c.cachedPodCost(pod).TotalCost
+	c.SetCostBasis(CostBasisLimits)
+	if limits := c.cachedPodCost(pod).TotalCost; limits <= requests {
+		t.Errorf("cost after switching to limits = %g, want more than %g", limits, requests)
+	}
+}
+
+func TestParseCostBasis(t *testing.T) {
+	for _, s := range []string{"requests", "limits", "max"} {
+		if basis, err := ParseCostBasis(s); err != nil || string(basis) != s {
+			t.Errorf("ParseCostBasis(%q) = %q, %v", s, basis, err)
+		}
+	}
+	for _, s := range []string{"", "request", "LIMITS", "average"} {
+		if _, err := ParseCostBasis(s); err == nil {
+			t.Errorf("ParseCostBasis(%q): expected an error", s)
+		}
+	}
+}
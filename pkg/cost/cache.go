@@ -0,0 +1,102 @@
+package cost
+
+import (
+	"hash"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podCostCache memoizes PodCost values keyed by a hash of a pod's resource
+// requirements, so identical specs (replicas, repeated runs) are only costed once
+type podCostCache struct {
+	mu      sync.Mutex
+	entries map[uint64]PodCost
+}
+
+// EnableCache turns on in-memory caching of pod costs. Cached entries are
+// keyed by resource requirements only; identity fields are always taken
+// from the pod being costed.
+func (c *Calculator) EnableCache() {
+	if c.cache == nil {
+		c.cache = &podCostCache{entries: make(map[uint64]PodCost)}
+	}
+}
+
+// cachedPodCost returns the cost for a pod, using the cache when enabled
+func (c *Calculator) cachedPodCost(pod corev1.Pod) PodCost {
+	if c.cache == nil {
+		return c.calculatePodCost(pod)
+	}
+
+	key := resourceHash(pod)
+
+	c.cache.mu.Lock()
+	cached, ok := c.cache.entries[key]
+	c.cache.mu.Unlock()
+
+	if !ok {
+		cached = c.calculatePodCost(pod)
+		c.cache.mu.Lock()
+		c.cache.entries[key] = cached
+		c.cache.mu.Unlock()
+	}
+
+	cached.Name = pod.Name
+	cached.Namespace = pod.Namespace
+	cached.Node = pod.Spec.NodeName
+	cached.ExtendedResources = copyExtendedResources(cached.ExtendedResources)
+	return cached
+}
+
+// copyExtendedResources gives each pod its own map so entries cached for one
+// pod are never shared with (or modified through) another
+func copyExtendedResources(in map[corev1.ResourceName]ExtendedResourceCost) map[corev1.ResourceName]ExtendedResourceCost {
+	if in == nil {
+		return nil
+	}
+	out := make(map[corev1.ResourceName]ExtendedResourceCost, len(in))
+	for name, rc := range in {
+		out[name] = rc
+	}
+	return out
+}
+
+// resourceHash hashes the requests and limits of every container in a pod.
+// It runs once per pod, so it writes canonical quantity bytes into a reused
+// buffer rather than formatting strings.
+func resourceHash(pod corev1.Pod) uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 0, 64)
+	for _, container := range pod.Spec.Containers {
+		buf = writeResourceList(h, buf, 'r', container.Resources.Requests)
+		buf = writeResourceList(h, buf, 'l', container.Resources.Limits)
+		h.Write([]byte{'|'})
+	}
+	return h.Sum64()
+}
+
+func writeResourceList(h hash.Hash64, buf []byte, prefix byte, list corev1.ResourceList) []byte {
+	var names [8]corev1.ResourceName
+	sorted := names[:0]
+	for name := range list {
+		sorted = append(sorted, name)
+	}
+	slices.Sort(sorted)
+
+	for _, name := range sorted {
+		q := list[name]
+		buf = append(buf[:0], prefix, ':')
+		buf = append(buf, name...)
+		buf = append(buf, '=')
+		mantissa, exponent := q.AsCanonicalBytes(buf)
+		buf = append(mantissa, 'e')
+		buf = strconv.AppendInt(buf, int64(exponent), 10)
+		buf = append(buf, ';')
+		h.Write(buf)
+	}
+	return buf
+}
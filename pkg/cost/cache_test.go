@@ -0,0 +1,89 @@
+package cost
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func replicaPods(n int) []corev1.Pod {
+	pods := make([]corev1.Pod, 0, n)
+	for i := 0; i < n; i++ {
+		pods = append(pods, testPod(fmt.Sprintf("api-%d", i), corev1.Container{
+			Name: "api",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+					"example.com/fpga":    resource.MustParse("1"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+		}))
+	}
+	return pods
+}
+
+func TestCachedPodCostIdentityAndIsolation(t *testing.T) {
+	c := NewCalculator()
+	c.EnableCache()
+	pods := replicaPods(2)
+
+	first := c.cachedPodCost(pods[0])
+	second := c.cachedPodCost(pods[1])
+
+	if first.Name != "api-0" || second.Name != "api-1" {
+		t.Errorf("names = %q, %q, want api-0, api-1", first.Name, second.Name)
+	}
+	if first.TotalCost != second.TotalCost {
+		t.Errorf("identical specs cost %g and %g", first.TotalCost, second.TotalCost)
+	}
+
+	first.ExtendedResources["example.com/fpga"] = ExtendedResourceCost{Quantity: "changed"}
+	if got := second.ExtendedResources["example.com/fpga"].Quantity; got != "1" {
+		t.Errorf("ExtendedResources shared between pods: got quantity %q, want 1", got)
+	}
+}
+
+func BenchmarkCalculatePodCosts(b *testing.B) {
+	pods := replicaPods(100)
+
+	b.Run("nocache", func(b *testing.B) {
+		c := NewCalculator()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			c.CalculatePodCosts(pods, nil)
+		}
+	})
+
+	b.Run("cache", func(b *testing.B) {
+		c := NewCalculator()
+		c.EnableCache()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			c.CalculatePodCosts(pods, nil)
+		}
+	})
+}
+
+func TestResourceHashDistinguishesExponent(t *testing.T) {
+	pod := func(mem string) corev1.Pod {
+		return testPod("p", corev1.Container{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(mem)},
+			},
+		})
+	}
+
+	if resourceHash(pod("1G")) == resourceHash(pod("1M")) {
+		t.Error("1G and 1M hash to the same key")
+	}
+	if resourceHash(pod("1Gi")) != resourceHash(pod("1073741824")) {
+		t.Error("equal quantities in different notation hash differently")
+	}
+}
@@ -2,6 +2,8 @@ package cost
 
 import (
 	"sort"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -15,23 +17,46 @@ type PodCost struct {
 	CPUCost    float64
 	MemoryCost float64
 	GPUCost    float64
-	GPUCount   int
+	GPUCount   float64
+	OS         string  // Node operating system: linux (default) or windows
+	OtherCost  float64 // Extended resources (see ExtendedResources)
 	TotalCost  float64
 	CPURequest string
 	MemRequest string
 	CPULimit   string
 	MemLimit   string
+
+	// TotalCost is the provisioned monthly rate. ProratedCost is only set
+	// when proration is enabled and is what the pod actually cost over its
+	// observed lifetime (short-lived pods only; others equal TotalCost).
+	AgeHours     float64
+	ShortLived   bool
+	ProratedCost float64
+
+	ExtendedResources map[corev1.ResourceName]ExtendedResourceCost
+}
+
+// ExtendedResourceCost is the cost of a single extended resource in a pod.
+// Resources without a configured rate are reported with a zero cost.
+type ExtendedResourceCost struct {
+	Quantity string
+	Cost     float64
+	Priced   bool
 }
 
 // Calculator handles cost calculations
 type Calculator struct {
 	pricing *Pricing
+	cache   *podCostCache
+	prorate bool
+	basis   CostBasis
 }
 
 // NewCalculator creates a new cost calculator
 func NewCalculator() *Calculator {
 	return &Calculator{
 		pricing: DefaultPricing(),
+		basis:   CostBasisRequests,
 	}
 }
 
@@ -39,19 +64,40 @@ func NewCalculator() *Calculator {
 func NewCalculatorWithPricing(pricing *Pricing) *Calculator {
 	return &Calculator{
 		pricing: pricing,
+		basis:   CostBasisRequests,
 	}
 }
 
 // CalculatePodCosts calculates costs for all pods
 func (c *Calculator) CalculatePodCosts(pods []corev1.Pod, nodes []corev1.Node) []PodCost {
 	results := make([]PodCost, 0, len(pods))
+	now := time.Now()
+
+	spotNodes := make(map[string]bool)
+	windowsNodes := make(map[string]bool)
+	for _, node := range nodes {
+		if c.pricing.SpotDiscount > 0 && NodeCapacityType(node) == CapacitySpot {
+			spotNodes[node.Name] = true
+		}
+		if NodeOS(node) == OSWindows {
+			windowsNodes[node.Name] = true
+		}
+	}
 
 	for _, pod := range pods {
 		if pod.Status.Phase != corev1.PodRunning {
 			continue
 		}
 
-		cost := c.calculatePodCost(pod)
+		cost := c.cachedPodCost(pod)
+		cost.OS = OSLinux
+		if windowsNodes[cost.Node] {
+			c.applyWindowsPremium(&cost)
+		}
+		if spotNodes[cost.Node] {
+			c.applySpotDiscount(&cost)
+		}
+		c.annotateLifetime(&cost, pod, now)
 		results = append(results, cost)
 	}
 
@@ -66,7 +112,8 @@ func (c *Calculator) CalculatePodCosts(pods []corev1.Pod, nodes []corev1.Node) [
 // calculatePodCost calculates the cost for a single pod
 func (c *Calculator) calculatePodCost(pod corev1.Pod) PodCost {
 	var cpuRequest, memRequest, cpuLimit, memLimit resource.Quantity
-	gpuCount := 0
+	var cpuToUse, memToUse resource.Quantity
+	gpuCount := 0.0
 
 	// Sum up all container resources
 	for _, container := range pod.Spec.Containers {
@@ -83,28 +130,18 @@ func (c *Calculator) calculatePodCost(pod corev1.Pod) PodCost {
 			memLimit.Add(lim)
 		}
 
-		// Check for GPU requests
-		if gpu, ok := container.Resources.Requests["nvidia.com/gpu"]; ok {
-			gpuCount += int(gpu.Value())
-		}
-		if gpu, ok := container.Resources.Limits["nvidia.com/gpu"]; ok {
-			gpuCount += int(gpu.Value())
-		}
-	}
+		// Pick what to cost according to the cost basis
+		cpuToUse.Add(c.billableQuantity(container, corev1.ResourceCPU))
+		memToUse.Add(c.billableQuantity(container, corev1.ResourceMemory))
 
-	// Calculate costs based on requests (or limits if requests not set)
-	cpuToUse := cpuRequest
-	if cpuToUse.IsZero() {
-		cpuToUse = cpuLimit
-	}
-	memToUse := memRequest
-	if memToUse.IsZero() {
-		memToUse = memLimit
+		// Check for GPU requests (fractional for shared GPUs, e.g. 500m)
+		gpuCount += ContainerGPUs(container)
 	}
 
 	cpuCost := c.pricing.CalculateCPUCost(cpuToUse.AsApproximateFloat64())
-	memCost := c.pricing.CalculateMemoryCost(memToUse.Value())
+	memCost := c.pricing.CalculateMemoryCost(memToUse.AsApproximateFloat64())
 	gpuCost := c.pricing.CalculateGPUCost(gpuCount)
+	extended, otherCost := c.calculateExtendedResources(pod)
 
 	return PodCost{
 		Name:       pod.Name,
@@ -114,12 +151,92 @@ func (c *Calculator) calculatePodCost(pod corev1.Pod) PodCost {
 		MemoryCost: memCost,
 		GPUCost:    gpuCost,
 		GPUCount:   gpuCount,
-		TotalCost:  cpuCost + memCost + gpuCost,
+		OtherCost:  otherCost,
+		TotalCost:  cpuCost + memCost + gpuCost + otherCost,
 		CPURequest: cpuRequest.String(),
 		MemRequest: memRequest.String(),
 		CPULimit:   cpuLimit.String(),
 		MemLimit:   memLimit.String(),
+
+		ExtendedResources: extended,
+	}
+}
+
+// applyWindowsPremium marks a pod as running on Windows and scales its CPU
+// and memory cost by the Windows licensing premium
+func (c *Calculator) applyWindowsPremium(pc *PodCost) {
+	multiplier := c.pricing.windowsMultiplier()
+	pc.OS = OSWindows
+	pc.CPUCost *= multiplier
+	pc.MemoryCost *= multiplier
+	pc.TotalCost = pc.CPUCost + pc.MemoryCost + pc.GPUCost + pc.OtherCost
+}
+
+// applySpotDiscount scales a pod's instance costs (CPU, memory, GPU) by the
+// configured spot discount. Extended resources are priced separately and
+// are left unchanged.
+func (c *Calculator) applySpotDiscount(pc *PodCost) {
+	factor := 1 - c.pricing.SpotDiscount
+	pc.CPUCost *= factor
+	pc.MemoryCost *= factor
+	pc.GPUCost *= factor
+	pc.TotalCost = pc.CPUCost + pc.MemoryCost + pc.GPUCost + pc.OtherCost
+}
+
+// calculateExtendedResources prices every requested resource outside the
+// known CPU/memory/GPU set, using limits when no request is set
+func (c *Calculator) calculateExtendedResources(pod corev1.Pod) (map[corev1.ResourceName]ExtendedResourceCost, float64) {
+	quantities := make(map[corev1.ResourceName]*resource.Quantity)
+	add := func(name corev1.ResourceName, q resource.Quantity) {
+		if isKnownResource(name) {
+			return
+		}
+		if total, ok := quantities[name]; ok {
+			total.Add(q)
+			return
+		}
+		qc := q.DeepCopy()
+		quantities[name] = &qc
+	}
+
+	for _, container := range pod.Spec.Containers {
+		for name, q := range container.Resources.Requests {
+			add(name, q)
+		}
+		for name, q := range container.Resources.Limits {
+			if _, ok := container.Resources.Requests[name]; !ok {
+				add(name, q)
+			}
+		}
+	}
+
+	if len(quantities) == 0 {
+		return nil, 0
+	}
+
+	extended := make(map[corev1.ResourceName]ExtendedResourceCost, len(quantities))
+	otherCost := 0.0
+	for name, q := range quantities {
+		cost, priced := c.pricing.CalculateExtendedResourceCost(name, q.AsApproximateFloat64())
+		extended[name] = ExtendedResourceCost{
+			Quantity: q.String(),
+			Cost:     cost,
+			Priced:   priced,
+		}
+		otherCost += cost
 	}
+
+	return extended, otherCost
+}
+
+// isKnownResource reports whether a resource is costed by the core CPU,
+// memory or GPU calculation (or is not billable on its own)
+func isKnownResource(name corev1.ResourceName) bool {
+	switch name {
+	case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage, "nvidia.com/gpu":
+		return true
+	}
+	return strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix)
 }
 
 // CalculateNodeCost calculates the total cost for a node
@@ -128,14 +245,36 @@ func (c *Calculator) CalculateNodeCost(node corev1.Node) float64 {
 	mem := node.Status.Capacity[corev1.ResourceMemory]
 
 	cpuCost := c.pricing.CalculateCPUCost(cpu.AsApproximateFloat64())
-	memCost := c.pricing.CalculateMemoryCost(mem.Value())
+	memCost := c.pricing.CalculateMemoryCost(mem.AsApproximateFloat64())
+	if NodeOS(node) == OSWindows {
+		cpuCost *= c.pricing.windowsMultiplier()
+		memCost *= c.pricing.windowsMultiplier()
+	}
 
 	// Check for GPUs
 	gpuCount := 0
 	if gpu, ok := node.Status.Capacity["nvidia.com/gpu"]; ok {
 		gpuCount = int(gpu.Value())
 	}
-	gpuCost := c.pricing.CalculateGPUCost(gpuCount)
+	gpuCost := c.pricing.CalculateGPUCost(float64(gpuCount))
 
-	return cpuCost + memCost + gpuCost
+	total := cpuCost + memCost + gpuCost
+	if c.pricing.SpotDiscount > 0 && NodeCapacityType(node) == CapacitySpot {
+		total *= 1 - c.pricing.SpotDiscount
+	}
+	return total
+}
+
+// ContainerGPUs returns the number of GPUs a container asks for. Requests are
+// preferred and limits are used as a fallback, so a container that sets both
+// is not counted twice. Quantities are read as floats so that fractional
+// shares such as "500m" are kept instead of being truncated to zero.
+func ContainerGPUs(container corev1.Container) float64 {
+	if gpu, ok := container.Resources.Requests["nvidia.com/gpu"]; ok {
+		return gpu.AsApproximateFloat64()
+	}
+	if gpu, ok := container.Resources.Limits["nvidia.com/gpu"]; ok {
+		return gpu.AsApproximateFloat64()
+	}
+	return 0
 }
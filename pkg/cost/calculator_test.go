@@ -0,0 +1,141 @@
+package cost
+
+import (
+	"math"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPod(name string, containers ...corev1.Container) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node-1", Containers: containers},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func gpuContainer(requests, limits string) corev1.Container {
+	c := corev1.Container{Name: "c"}
+	if requests != "" {
+		c.Resources.Requests = corev1.ResourceList{"nvidia.com/gpu": resource.MustParse(requests)}
+	}
+	if limits != "" {
+		c.Resources.Limits = corev1.ResourceList{"nvidia.com/gpu": resource.MustParse(limits)}
+	}
+	return c
+}
+
+func TestContainerGPUs(t *testing.T) {
+	tests := []struct {
+		name      string
+		container corev1.Container
+		want      float64
+	}{
+		{"fractional request", gpuContainer("500m", ""), 0.5},
+		{"fractional limit only", gpuContainer("", "500m"), 0.5},
+		{"request and limit counted once", gpuContainer("1", "1"), 1},
+		{"shared request and limit counted once", gpuContainer("500m", "500m"), 0.5},
+		{"no gpu", corev1.Container{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContainerGPUs(tt.container); got != tt.want {
+				t.Errorf("ContainerGPUs() = %g, want %g", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculatePodCostFractionalGPU(t *testing.T) {
+	c := NewCalculator()
+	pod := testPod("shared", gpuContainer("500m", "500m"))
+
+	got := c.calculatePodCost(pod)
+	if got.GPUCount != 0.5 {
+		t.Errorf("GPUCount = %g, want 0.5", got.GPUCount)
+	}
+	if want := c.pricing.CalculateGPUCost(0.5); got.GPUCost != want {
+		t.Errorf("GPUCost = %g, want %g", got.GPUCost, want)
+	}
+}
+
+func TestCalculatePodCostHugeMemory(t *testing.T) {
+	huge := corev1.Container{
+		Name: "c",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Ei")},
+		},
+	}
+	// Four 8Ei requests sum to 32Ei, well past the int64 byte range
+	pod := testPod("huge", huge, huge, huge, huge)
+
+	c := NewCalculator()
+	got := c.calculatePodCost(pod)
+	single := c.calculatePodCost(testPod("single", huge))
+
+	for name, v := range map[string]float64{"MemoryCost": got.MemoryCost, "TotalCost": got.TotalCost} {
+		if v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+			t.Errorf("%s = %g, want positive and finite", name, v)
+		}
+	}
+	if ratio := got.MemoryCost / single.MemoryCost; math.Abs(ratio-4) > 1e-9 {
+		t.Errorf("4 × 8Ei costs %g× a single 8Ei, want 4×", ratio)
+	}
+}
+
+func TestCalculateExtendedResources(t *testing.T) {
+	pricing := DefaultPricing()
+	pricing.ExtendedResourcePricing = map[corev1.ResourceName]float64{"squat.ai/fuse": 0.05}
+	c := NewCalculatorWithPricing(pricing)
+
+	pod := testPod("devices",
+		corev1.Container{
+			Name: "priced",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{"squat.ai/fuse": resource.MustParse("2")},
+				Limits:   corev1.ResourceList{"squat.ai/fuse": resource.MustParse("2")},
+			},
+		},
+		corev1.Container{
+			Name: "unpriced",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{"example.com/fpga": resource.MustParse("1")},
+			},
+		},
+		corev1.Container{
+			Name: "limit-only",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					"squat.ai/fuse":       resource.MustParse("1"),
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	)
+
+	extended, otherCost := c.calculateExtendedResources(pod)
+
+	// 2 requested + 1 from the limit-only container, counted once each
+	fuse := extended["squat.ai/fuse"]
+	wantFuse := 3 * 0.05 * 730
+	if !fuse.Priced || fuse.Quantity != "3" || math.Abs(fuse.Cost-wantFuse) > 1e-9 {
+		t.Errorf("squat.ai/fuse = %+v, want 3 priced at %g", fuse, wantFuse)
+	}
+
+	fpga := extended["example.com/fpga"]
+	if fpga.Priced || fpga.Quantity != "1" || fpga.Cost != 0 {
+		t.Errorf("example.com/fpga = %+v, want quantity 1, unpriced at $0", fpga)
+	}
+
+	if len(extended) != 2 {
+		t.Errorf("extended resources = %v, want only squat.ai/fuse and example.com/fpga", extended)
+	}
+	if math.Abs(otherCost-wantFuse) > 1e-9 {
+		t.Errorf("otherCost = %g, want %g", otherCost, wantFuse)
+	}
+}
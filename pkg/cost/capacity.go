@@ -0,0 +1,107 @@
+package cost
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Node capacity types
+const (
+	CapacitySpot     = "spot"
+	CapacityOnDemand = "on-demand"
+)
+
+// capacityTypeLabels are the node labels cloud providers and autoscalers use
+// to mark spot/preemptible capacity, with the value meaning "spot". Labels
+// marked spotOnly are only ever set on spot nodes (GKE, AKS), so their
+// on-demand nodes carry no capacity label at all.
+var capacityTypeLabels = []struct {
+	key      string
+	spot     string
+	spotOnly bool
+}{
+	{"karpenter.sh/capacity-type", "spot", false},
+	{"eks.amazonaws.com/capacityType", "SPOT", false},
+	{"cloud.google.com/gke-spot", "true", true},
+	{"cloud.google.com/gke-preemptible", "true", true},
+	{"kubernetes.azure.com/scalesetpriority", "spot", true},
+	{"node.kubernetes.io/lifecycle", "spot", false},
+}
+
+// Node operating systems
+const (
+	OSLinux   = "linux"
+	OSWindows = "windows"
+)
+
+// NodeOS returns the node's operating system from its reported node info or
+// the kubernetes.io/os label, defaulting to Linux when unknown
+func NodeOS(node corev1.Node) string {
+	if strings.EqualFold(node.Status.NodeInfo.OperatingSystem, OSWindows) ||
+		strings.EqualFold(node.Labels[corev1.LabelOSStable], OSWindows) {
+		return OSWindows
+	}
+	return OSLinux
+}
+
+// CapacityMix counts nodes by capacity type
+type CapacityMix struct {
+	Spot     int
+	OnDemand int
+	Unknown  int
+}
+
+// Mixed reports whether both spot and on-demand nodes are present
+func (m CapacityMix) Mixed() bool {
+	return m.Spot > 0 && m.OnDemand > 0
+}
+
+// NodeCapacityType returns CapacitySpot or CapacityOnDemand based on node
+// labels, or "" when the node carries none of the known labels
+func NodeCapacityType(node corev1.Node) string {
+	capacityType, _ := nodeCapacityLabel(node)
+	return capacityType
+}
+
+// nodeCapacityLabel returns the node's capacity type and whether it came
+// from a label that providers only set on spot nodes
+func nodeCapacityLabel(node corev1.Node) (string, bool) {
+	for _, label := range capacityTypeLabels {
+		value, ok := node.Labels[label.key]
+		if !ok {
+			continue
+		}
+		if strings.EqualFold(value, label.spot) {
+			return CapacitySpot, label.spotOnly
+		}
+		return CapacityOnDemand, false
+	}
+	return "", false
+}
+
+// DetectCapacityMix inspects node labels for spot and on-demand capacity.
+// When spot nodes are marked with a spot-only label (GKE, AKS), unlabeled
+// nodes are counted as on-demand rather than unknown.
+func DetectCapacityMix(nodes []corev1.Node) CapacityMix {
+	var mix CapacityMix
+	spotOnly := false
+	for _, node := range nodes {
+		capacityType, fromSpotOnly := nodeCapacityLabel(node)
+		switch capacityType {
+		case CapacitySpot:
+			mix.Spot++
+			spotOnly = spotOnly || fromSpotOnly
+		case CapacityOnDemand:
+			mix.OnDemand++
+		default:
+			mix.Unknown++
+		}
+	}
+
+	if spotOnly {
+		mix.OnDemand += mix.Unknown
+		mix.Unknown = 0
+	}
+	return mix
+}
@@ -0,0 +1,111 @@
+package cost
+
+import (
+	"math"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func labeledNode(name string, labels map[string]string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{Capacity: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("16Gi"),
+		}},
+	}
+}
+
+func TestDetectCapacityMix(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []corev1.Node
+		want  CapacityMix
+		mixed bool
+	}{
+		{
+			name: "eks labels both kinds",
+			nodes: []corev1.Node{
+				labeledNode("a", map[string]string{"eks.amazonaws.com/capacityType": "SPOT"}),
+				labeledNode("b", map[string]string{"eks.amazonaws.com/capacityType": "ON_DEMAND"}),
+				labeledNode("c", nil),
+			},
+			want:  CapacityMix{Spot: 1, OnDemand: 1, Unknown: 1},
+			mixed: true,
+		},
+		{
+			name: "gke labels only spot nodes",
+			nodes: []corev1.Node{
+				labeledNode("a", map[string]string{"cloud.google.com/gke-spot": "true"}),
+				labeledNode("b", map[string]string{"cloud.google.com/gke-nodepool": "default-pool"}),
+				labeledNode("c", nil),
+			},
+			want:  CapacityMix{Spot: 1, OnDemand: 2},
+			mixed: true,
+		},
+		{
+			name: "gke preemptible",
+			nodes: []corev1.Node{
+				labeledNode("a", map[string]string{"cloud.google.com/gke-preemptible": "true"}),
+				labeledNode("b", nil),
+			},
+			want:  CapacityMix{Spot: 1, OnDemand: 1},
+			mixed: true,
+		},
+		{
+			name: "aks labels only spot nodes",
+			nodes: []corev1.Node{
+				labeledNode("a", map[string]string{"kubernetes.azure.com/scalesetpriority": "spot"}),
+				labeledNode("b", map[string]string{"kubernetes.azure.com/agentpool": "system"}),
+			},
+			want:  CapacityMix{Spot: 1, OnDemand: 1},
+			mixed: true,
+		},
+		{
+			name: "no labels at all",
+			nodes: []corev1.Node{
+				labeledNode("a", nil),
+				labeledNode("b", nil),
+			},
+			want: CapacityMix{Unknown: 2},
+		},
+		{
+			name: "all gke spot",
+			nodes: []corev1.Node{
+				labeledNode("a", map[string]string{"cloud.google.com/gke-spot": "true"}),
+			},
+			want: CapacityMix{Spot: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectCapacityMix(tt.nodes)
+			if got != tt.want {
+				t.Errorf("DetectCapacityMix = %+v, want %+v", got, tt.want)
+			}
+			if got.Mixed() != tt.mixed {
+				t.Errorf("Mixed() = %v, want %v", got.Mixed(), tt.mixed)
+			}
+		})
+	}
+}
+
+func TestCalculateNodeCostSpotDiscount(t *testing.T) {
+	pricing := DefaultPricing()
+	pricing.SpotDiscount = 0.6
+	c := NewCalculatorWithPricing(pricing)
+
+	onDemand := c.CalculateNodeCost(labeledNode("a", nil))
+	spot := c.CalculateNodeCost(labeledNode("b", map[string]string{"karpenter.sh/capacity-type": "spot"}))
+
+	if want := onDemand * 0.4; math.Abs(spot-want) > 1e-9 {
+		t.Errorf("spot node cost = %g, want %g (60%% off %g)", spot, want, onDemand)
+	}
+	if undiscounted := NewCalculator().CalculateNodeCost(labeledNode("a", nil)); onDemand != undiscounted {
+		t.Errorf("on-demand node cost = %g, want undiscounted %g", onDemand, undiscounted)
+	}
+}
@@ -0,0 +1,49 @@
+package cost
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Highlight is a single named cost winner
+type Highlight struct {
+	Name string
+	Cost float64
+}
+
+// TopSpenders holds the most expensive namespace, node and pod. Entries
+// with an empty Name mean there was nothing to rank.
+type TopSpenders struct {
+	Namespace Highlight
+	Node      Highlight
+	Pod       Highlight
+}
+
+// Highlights picks the most expensive namespace and pod (by workload cost)
+// and the most expensive node (by provisioned cost) from computed costs
+func (c *Calculator) Highlights(costs []PodCost, nodes []corev1.Node) TopSpenders {
+	var top TopSpenders
+
+	byNamespace := make(map[string]float64)
+	for _, pc := range costs {
+		byNamespace[pc.Namespace] += pc.TotalCost
+		if top.Pod.Name == "" || pc.TotalCost > top.Pod.Cost {
+			top.Pod = Highlight{Name: pc.Namespace + "/" + pc.Name, Cost: pc.TotalCost}
+		}
+	}
+
+	for ns, total := range byNamespace {
+		if top.Namespace.Name == "" || total > top.Namespace.Cost ||
+			(total == top.Namespace.Cost && ns < top.Namespace.Name) {
+			top.Namespace = Highlight{Name: ns, Cost: total}
+		}
+	}
+
+	for _, node := range nodes {
+		nodeCost := c.CalculateNodeCost(node)
+		if top.Node.Name == "" || nodeCost > top.Node.Cost {
+			top.Node = Highlight{Name: node.Name, Cost: nodeCost}
+		}
+	}
+
+	return top
+}
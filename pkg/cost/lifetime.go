@@ -0,0 +1,59 @@
+package cost
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ShortLivedAge is the age below which a pod is considered short-lived
+const ShortLivedAge = 24 * time.Hour
+
+// hoursPerMonth matches the monthly basis used by Pricing
+const hoursPerMonth = 730.0
+
+// EnableProration makes the calculator fill PodCost.ProratedCost with the
+// cost actually incurred over each pod's observed lifetime
+func (c *Calculator) EnableProration() {
+	c.prorate = true
+}
+
+// annotateLifetime records the pod's age and, when proration is enabled,
+// scales the monthly rate of short-lived pods down to their observed lifetime
+func (c *Calculator) annotateLifetime(pc *PodCost, pod corev1.Pod, now time.Time) {
+	age := podAge(pod, now)
+	pc.AgeHours = age.Hours()
+	pc.ShortLived = isJobPod(pod) || age < ShortLivedAge
+
+	if !c.prorate {
+		return
+	}
+
+	pc.ProratedCost = pc.TotalCost
+	if pc.ShortLived && pc.AgeHours < hoursPerMonth {
+		pc.ProratedCost = pc.TotalCost * (pc.AgeHours / hoursPerMonth)
+	}
+}
+
+// podAge returns how long the pod has been running, falling back to its
+// creation time when the start time is not yet set
+func podAge(pod corev1.Pod, now time.Time) time.Duration {
+	start := pod.CreationTimestamp.Time
+	if pod.Status.StartTime != nil {
+		start = pod.Status.StartTime.Time
+	}
+	if start.IsZero() || start.After(now) {
+		return 0
+	}
+	return now.Sub(start)
+}
+
+// isJobPod reports whether the pod is owned by a Job or CronJob
+func isJobPod(pod corev1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "Job" || owner.Kind == "CronJob" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package cost
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NetworkCost is a cluster-level estimate of network spend. Pod-level egress
+// is not exposed by the Kubernetes API, so these figures are derived from
+// LoadBalancer Services and an assumed per-pod egress volume.
+type NetworkCost struct {
+	LoadBalancers    int
+	LoadBalancerCost float64
+	EgressGB         float64
+	EgressCost       float64
+	TotalCost        float64
+	Estimate         bool
+}
+
+// EstimateNetworkCost estimates monthly network cost from the LoadBalancer
+// Services present and an assumed egress volume (GB/month) per running pod
+func (c *Calculator) EstimateNetworkCost(pods []corev1.Pod, services []corev1.Service, egressGBPerPod float64) NetworkCost {
+	network := NetworkCost{Estimate: true}
+
+	for _, svc := range services {
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			network.LoadBalancers++
+		}
+	}
+
+	running := 0
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			running++
+		}
+	}
+
+	network.LoadBalancerCost = c.pricing.CalculateLoadBalancerCost(network.LoadBalancers)
+	network.EgressGB = float64(running) * egressGBPerPod
+	network.EgressCost = c.pricing.CalculateEgressCost(network.EgressGB)
+	network.TotalCost = network.LoadBalancerCost + network.EgressCost
+
+	return network
+}
@@ -1,61 +1,130 @@
 package cost
 
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
 // Pricing contains the pricing information for resources
 type Pricing struct {
-	CPUHourlyCost    float64 // Cost per CPU core per hour
-	MemoryGBHourly   float64 // Cost per GB memory per hour
-	GPUHourlyCost    float64 // Cost per GPU per hour
-	StorageGBMonthly float64 // Cost per GB storage per month
+	CPUHourlyCost       float64 // Cost per CPU core per hour
+	MemoryGBHourly      float64 // Cost per GB memory per hour
+	GPUHourlyCost       float64 // Cost per GPU per hour
+	StorageGBMonthly    float64 // Cost per GB storage per month
+	EgressGBCost        float64 // Cost per GB of network egress
+	LoadBalancerMonthly float64 // Flat cost per LoadBalancer Service per month
+	SpotDiscount        float64 // Fraction off for pods on spot nodes (0.7 = 70% off)
+
+	// WindowsPremiumMultiplier scales CPU and memory cost on Windows nodes
+	// to account for licensing (1.0 or 0 = no premium)
+	WindowsPremiumMultiplier float64
+
+	// ExtendedResourcePricing holds hourly rates for extended resources
+	// (e.g. squat.ai/fuse, FPGA devices), per unit
+	ExtendedResourcePricing map[corev1.ResourceName]float64
 }
 
 // DefaultPricing returns default AWS-like pricing
 // Based on typical m5.large pricing (~$0.096/hour)
 func DefaultPricing() *Pricing {
 	return &Pricing{
-		CPUHourlyCost:    0.024, // ~$17.28/month per core
-		MemoryGBHourly:   0.003, // ~$2.16/month per GB
-		GPUHourlyCost:    0.90,  // ~$648/month per GPU (T4)
-		StorageGBMonthly: 0.10,  // ~$0.10/month per GB (EBS gp3)
+		CPUHourlyCost:       0.024, // ~$17.28/month per core
+		MemoryGBHourly:      0.003, // ~$2.16/month per GB
+		GPUHourlyCost:       0.90,  // ~$648/month per GPU (T4)
+		StorageGBMonthly:    0.10,  // ~$0.10/month per GB (EBS gp3)
+		EgressGBCost:        0.09,  // internet egress, first 10TB
+		LoadBalancerMonthly: 16.43, // ~$0.0225/hour per ELB
+
+		WindowsPremiumMultiplier: 1.9, // m5 Windows vs Linux on-demand
 	}
 }
 
 // GCPPricing returns Google Cloud pricing
 func GCPPricing() *Pricing {
 	return &Pricing{
-		CPUHourlyCost:    0.022, // n2-standard pricing
-		MemoryGBHourly:   0.003,
-		GPUHourlyCost:    0.85, // T4 GPU
-		StorageGBMonthly: 0.10,
+		CPUHourlyCost:       0.022, // n2-standard pricing
+		MemoryGBHourly:      0.003,
+		GPUHourlyCost:       0.85, // T4 GPU
+		StorageGBMonthly:    0.10,
+		EgressGBCost:        0.12,
+		LoadBalancerMonthly: 18.25, // forwarding rule
+
+		WindowsPremiumMultiplier: 2.0, // Windows Server license per core
 	}
 }
 
 // AzurePricing returns Azure pricing
 func AzurePricing() *Pricing {
 	return &Pricing{
-		CPUHourlyCost:    0.025,
-		MemoryGBHourly:   0.003,
-		GPUHourlyCost:    0.95, // NC-series
-		StorageGBMonthly: 0.12,
+		CPUHourlyCost:       0.025,
+		MemoryGBHourly:      0.003,
+		GPUHourlyCost:       0.95, // NC-series
+		StorageGBMonthly:    0.12,
+		EgressGBCost:        0.087,
+		LoadBalancerMonthly: 18.25, // Standard Load Balancer
+
+		WindowsPremiumMultiplier: 1.8, // D-series Windows vs Linux
 	}
 }
 
+// BareMetalPricing derives hourly rates for owned hardware from a total
+// amortized monthly cost spread across the cluster's capacity. The cost is
+// split between CPU and memory in the same proportion as the default cloud
+// rates, then divided per core and per GB. GPU, storage and network are not
+// part of the amortization and are left at zero.
+func BareMetalPricing(cpuCoresTotal, memGBTotal int, monthlyAmortized float64) *Pricing {
+	pricing := &Pricing{WindowsPremiumMultiplier: 1}
+	if cpuCoresTotal <= 0 || memGBTotal <= 0 || monthlyAmortized <= 0 {
+		return pricing
+	}
+
+	hoursPerMonth := 730.0
+	reference := DefaultPricing()
+	cpuWeight := float64(cpuCoresTotal) * reference.CPUHourlyCost
+	memWeight := float64(memGBTotal) * reference.MemoryGBHourly
+	cpuShare := cpuWeight / (cpuWeight + memWeight)
+
+	pricing.CPUHourlyCost = monthlyAmortized * cpuShare / float64(cpuCoresTotal) / hoursPerMonth
+	pricing.MemoryGBHourly = monthlyAmortized * (1 - cpuShare) / float64(memGBTotal) / hoursPerMonth
+	return pricing
+}
+
 // CalculateCPUCost calculates monthly cost for CPU cores
 func (p *Pricing) CalculateCPUCost(cores float64) float64 {
 	hoursPerMonth := 730.0 // Average hours in a month
 	return cores * p.CPUHourlyCost * hoursPerMonth
 }
 
-// CalculateMemoryCost calculates monthly cost for memory
-func (p *Pricing) CalculateMemoryCost(bytes int64) float64 {
-	gb := float64(bytes) / (1024 * 1024 * 1024)
+// CalculateMemoryCost calculates monthly cost for memory. Bytes are taken as
+// a float64 so very large totals cannot wrap around like int64 would.
+func (p *Pricing) CalculateMemoryCost(bytes float64) float64 {
+	gb := bytes / (1024 * 1024 * 1024)
 	hoursPerMonth := 730.0
 	return gb * p.MemoryGBHourly * hoursPerMonth
 }
 
-// CalculateGPUCost calculates monthly cost for GPUs
-func (p *Pricing) CalculateGPUCost(count int) float64 {
+// windowsMultiplier returns the Windows premium, treating unset as none
+func (p *Pricing) windowsMultiplier() float64 {
+	if p.WindowsPremiumMultiplier <= 0 {
+		return 1
+	}
+	return p.WindowsPremiumMultiplier
+}
+
+// CalculateGPUCost calculates monthly cost for GPUs (fractional for shared GPUs)
+func (p *Pricing) CalculateGPUCost(count float64) float64 {
+	hoursPerMonth := 730.0
+	return count * p.GPUHourlyCost * hoursPerMonth
+}
+
+// CalculateExtendedResourceCost calculates monthly cost for an extended
+// resource. The second return value is false when no rate is configured.
+func (p *Pricing) CalculateExtendedResourceCost(name corev1.ResourceName, quantity float64) (float64, bool) {
+	rate, ok := p.ExtendedResourcePricing[name]
+	if !ok {
+		return 0, false
+	}
 	hoursPerMonth := 730.0
-	return float64(count) * p.GPUHourlyCost * hoursPerMonth
+	return quantity * rate * hoursPerMonth, true
 }
 
 // CalculateStorageCost calculates monthly cost for storage
@@ -63,3 +132,13 @@ func (p *Pricing) CalculateStorageCost(bytes int64) float64 {
 	gb := float64(bytes) / (1024 * 1024 * 1024)
 	return gb * p.StorageGBMonthly
 }
+
+// CalculateEgressCost calculates cost for network egress
+func (p *Pricing) CalculateEgressCost(gb float64) float64 {
+	return gb * p.EgressGBCost
+}
+
+// CalculateLoadBalancerCost calculates monthly cost for LoadBalancer Services
+func (p *Pricing) CalculateLoadBalancerCost(count int) float64 {
+	return float64(count) * p.LoadBalancerMonthly
+}
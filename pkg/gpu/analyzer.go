@@ -1,6 +1,12 @@
 package gpu
 
 import (
+	"fmt"
+	"math"
+	"sort"
+
+	"kcavo/pkg/cost"
+
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -12,16 +18,43 @@ type Analysis struct {
 	AllocatedGPUs   int
 	AvailableGPUs   int
 	UtilizationPct  float64
-	Recommendations []string
+	Recommendations []GPURecommendation
+	Warnings        []string // Data inconsistencies found while reconciling
+}
+
+// RecommendationType classifies a GPU recommendation for automation
+type RecommendationType string
+
+const (
+	LowUtilization  RecommendationType = "LowUtilization"
+	HighUtilization RecommendationType = "HighUtilization"
+	Fragmentation   RecommendationType = "Fragmentation"
+	NoGPU           RecommendationType = "NoGPU"
+	ConsiderMIG     RecommendationType = "ConsiderMIG"
+)
+
+// GPURecommendation is a single GPU scheduling recommendation
+type GPURecommendation struct {
+	Type     RecommendationType
+	Severity string // High, Medium, Low
+	Message  string
+	Details  string
+}
+
+// String returns the human-readable message
+func (r GPURecommendation) String() string {
+	return r.Message
 }
 
 // NodeGPU represents GPU information for a node
 type NodeGPU struct {
-	NodeName      string
-	TotalGPUs     int
-	AllocatedGPUs int
-	AvailableGPUs int
-	GPUType       string
+	NodeName        string
+	TotalGPUs       int
+	AllocatableGPUs int
+	RequestedGPUs   float64 // Sum of GPU requests of pods scheduled here
+	AllocatedGPUs   int     // Whole GPUs in use (a shared GPU counts as one)
+	AvailableGPUs   int
+	GPUType         string
 }
 
 // PodGPU represents GPU usage for a pod
@@ -29,44 +62,108 @@ type PodGPU struct {
 	PodName   string
 	Namespace string
 	Node      string
-	GPUCount  int
+	GPUCount  float64 // fractional for shared GPUs (e.g. 0.5 for 500m)
+}
+
+// Thresholds tunes the GPU utilization recommendations. Values are percentages.
+type Thresholds struct {
+	LowUtilizationPct  float64 // Below this, suggest scaling down
+	HighUtilizationPct float64 // Above this, suggest adding capacity
+}
+
+// DefaultThresholds returns the built-in GPU utilization thresholds
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		LowUtilizationPct:  50,
+		HighUtilizationPct: 85,
+	}
+}
+
+// Validate checks that thresholds are within 0–100 and low does not exceed high
+func (t Thresholds) Validate() error {
+	if t.LowUtilizationPct < 0 || t.LowUtilizationPct > 100 {
+		return fmt.Errorf("GPU low utilization threshold must be within 0-100, got %g", t.LowUtilizationPct)
+	}
+	if t.HighUtilizationPct < 0 || t.HighUtilizationPct > 100 {
+		return fmt.Errorf("GPU high utilization threshold must be within 0-100, got %g", t.HighUtilizationPct)
+	}
+	if t.LowUtilizationPct > t.HighUtilizationPct {
+		return fmt.Errorf("GPU low utilization threshold (%g) exceeds high threshold (%g)", t.LowUtilizationPct, t.HighUtilizationPct)
+	}
+	return nil
 }
 
 // Analyzer analyzes GPU resources
-type Analyzer struct{}
+type Analyzer struct {
+	thresholds Thresholds
+}
 
 // NewAnalyzer creates a new GPU analyzer
 func NewAnalyzer() *Analyzer {
-	return &Analyzer{}
+	return &Analyzer{
+		thresholds: DefaultThresholds(),
+	}
 }
 
-// Analyze performs GPU analysis on nodes and pods
+// NewAnalyzerWithThresholds creates a GPU analyzer with custom thresholds.
+// Thresholds should be checked with Validate beforehand.
+func NewAnalyzerWithThresholds(thresholds Thresholds) *Analyzer {
+	return &Analyzer{
+		thresholds: thresholds,
+	}
+}
+
+// Analyze performs GPU analysis on nodes and pods. Node availability is
+// reconciled against the GPU requests of the given pods scheduled on each
+// node, so pass pods from all namespaces and narrow the pod list afterwards
+// with FilterNamespace.
 func (a *Analyzer) Analyze(nodes []corev1.Node, pods []corev1.Pod) Analysis {
 	analysis := Analysis{
 		Nodes:           make([]NodeGPU, 0),
 		Pods:            make([]PodGPU, 0),
-		Recommendations: make([]string, 0),
+		Recommendations: make([]GPURecommendation, 0),
+		Warnings:        make([]string, 0),
+	}
+
+	// Analyze pods
+	requested := make(map[string]float64)
+	for _, pod := range pods {
+		podGPU := a.analyzePod(pod)
+		if podGPU.GPUCount > 0 {
+			analysis.Pods = append(analysis.Pods, podGPU)
+			if podGPU.Node != "" && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				requested[podGPU.Node] += podGPU.GPUCount
+			}
+		}
 	}
 
 	// Analyze nodes
+	gpuNodes := make(map[string]bool)
 	for _, node := range nodes {
 		nodeGPU := a.analyzeNode(node)
 		if nodeGPU.TotalGPUs > 0 {
+			analysis.Warnings = append(analysis.Warnings, reconcileNode(&nodeGPU, requested[node.Name])...)
 			analysis.Nodes = append(analysis.Nodes, nodeGPU)
 			analysis.TotalGPUs += nodeGPU.TotalGPUs
 			analysis.AllocatedGPUs += nodeGPU.AllocatedGPUs
+			analysis.AvailableGPUs += nodeGPU.AvailableGPUs
+			gpuNodes[node.Name] = true
 		}
 	}
 
-	// Analyze pods
-	for _, pod := range pods {
-		podGPU := a.analyzePod(pod)
-		if podGPU.GPUCount > 0 {
-			analysis.Pods = append(analysis.Pods, podGPU)
+	// Pods claiming GPUs on nodes that report none
+	nodeNames := make([]string, 0, len(requested))
+	for name := range requested {
+		if !gpuNodes[name] {
+			nodeNames = append(nodeNames, name)
 		}
 	}
+	sort.Strings(nodeNames)
+	for _, name := range nodeNames {
+		analysis.Warnings = append(analysis.Warnings,
+			fmt.Sprintf("node %s: pods request %g GPUs but the node reports no GPU capacity", name, requested[name]))
+	}
 
-	analysis.AvailableGPUs = analysis.TotalGPUs - analysis.AllocatedGPUs
 	if analysis.TotalGPUs > 0 {
 		analysis.UtilizationPct = (float64(analysis.AllocatedGPUs) / float64(analysis.TotalGPUs)) * 100
 	}
@@ -77,6 +174,47 @@ func (a *Analyzer) Analyze(nodes []corev1.Node, pods []corev1.Pod) Analysis {
 	return analysis
 }
 
+// FilterNamespace keeps only the pods in namespace (all pods if empty).
+// Node figures and recommendations are cluster-wide and left untouched.
+func (a *Analysis) FilterNamespace(namespace string) {
+	if namespace == "" {
+		return
+	}
+
+	pods := make([]PodGPU, 0, len(a.Pods))
+	for _, pod := range a.Pods {
+		if pod.Namespace == namespace {
+			pods = append(pods, pod)
+		}
+	}
+	a.Pods = pods
+}
+
+// reconcileNode derives a node's allocated and available GPUs from the
+// requests of pods scheduled on it and returns any inconsistencies found
+func reconcileNode(nodeGPU *NodeGPU, requested float64) []string {
+	warnings := make([]string, 0)
+
+	nodeGPU.RequestedGPUs = requested
+	// Fractional requests still occupy a whole physical GPU; the epsilon
+	// absorbs float error from summing shares like 0.1 + 0.2
+	nodeGPU.AllocatedGPUs = int(math.Ceil(requested - 1e-9))
+	nodeGPU.AvailableGPUs = nodeGPU.AllocatableGPUs - nodeGPU.AllocatedGPUs
+	if nodeGPU.AvailableGPUs < 0 {
+		nodeGPU.AvailableGPUs = 0
+	}
+
+	if requested > float64(nodeGPU.TotalGPUs) {
+		warnings = append(warnings, fmt.Sprintf("node %s: pods request %g GPUs but node capacity is %d",
+			nodeGPU.NodeName, requested, nodeGPU.TotalGPUs))
+	} else if requested > float64(nodeGPU.AllocatableGPUs) {
+		warnings = append(warnings, fmt.Sprintf("node %s: pods request %g GPUs but only %d are allocatable",
+			nodeGPU.NodeName, requested, nodeGPU.AllocatableGPUs))
+	}
+
+	return warnings
+}
+
 func (a *Analyzer) analyzeNode(node corev1.Node) NodeGPU {
 	nodeGPU := NodeGPU{
 		NodeName: node.Name,
@@ -88,10 +226,10 @@ func (a *Analyzer) analyzeNode(node corev1.Node) NodeGPU {
 		nodeGPU.TotalGPUs = int(gpu.Value())
 	}
 
-	// Get allocated GPUs from allocatable (capacity - allocated = available)
+	// Allocatable excludes GPUs reserved or unhealthy on the node
+	nodeGPU.AllocatableGPUs = nodeGPU.TotalGPUs
 	if gpu, ok := node.Status.Allocatable["nvidia.com/gpu"]; ok {
-		nodeGPU.AvailableGPUs = int(gpu.Value())
-		nodeGPU.AllocatedGPUs = nodeGPU.TotalGPUs - nodeGPU.AvailableGPUs
+		nodeGPU.AllocatableGPUs = int(gpu.Value())
 	}
 
 	// Try to detect GPU type from node labels
@@ -113,30 +251,33 @@ func (a *Analyzer) analyzePod(pod corev1.Pod) PodGPU {
 
 	// Count GPUs across all containers
 	for _, container := range pod.Spec.Containers {
-		if gpu, ok := container.Resources.Requests["nvidia.com/gpu"]; ok {
-			podGPU.GPUCount += int(gpu.Value())
-		}
-		if gpu, ok := container.Resources.Limits["nvidia.com/gpu"]; ok {
-			podGPU.GPUCount += int(gpu.Value())
-		}
+		podGPU.GPUCount += cost.ContainerGPUs(container)
 	}
 
 	return podGPU
 }
 
-func (a *Analyzer) generateRecommendations(analysis Analysis) []string {
-	recommendations := make([]string, 0)
+func (a *Analyzer) generateRecommendations(analysis Analysis) []GPURecommendation {
+	recommendations := make([]GPURecommendation, 0)
 
 	// Low utilization
-	if analysis.TotalGPUs > 0 && analysis.UtilizationPct < 50 {
-		recommendations = append(recommendations,
-			"GPU utilization is below 50%. Consider scaling down GPU nodes or consolidating workloads.")
+	if analysis.TotalGPUs > 0 && analysis.UtilizationPct < a.thresholds.LowUtilizationPct {
+		recommendations = append(recommendations, GPURecommendation{
+			Type:     LowUtilization,
+			Severity: "High",
+			Message:  fmt.Sprintf("GPU utilization is below %g%%. Consider scaling down GPU nodes or consolidating workloads.", a.thresholds.LowUtilizationPct),
+			Details:  fmt.Sprintf("%d of %d GPUs allocated (%.1f%%)", analysis.AllocatedGPUs, analysis.TotalGPUs, analysis.UtilizationPct),
+		})
 	}
 
 	// High utilization
-	if analysis.UtilizationPct > 85 {
-		recommendations = append(recommendations,
-			"GPU utilization is above 85%. Consider adding more GPU nodes to prevent scheduling issues.")
+	if analysis.UtilizationPct > a.thresholds.HighUtilizationPct {
+		recommendations = append(recommendations, GPURecommendation{
+			Type:     HighUtilization,
+			Severity: "Medium",
+			Message:  fmt.Sprintf("GPU utilization is above %g%%. Consider adding more GPU nodes to prevent scheduling issues.", a.thresholds.HighUtilizationPct),
+			Details:  fmt.Sprintf("%d of %d GPUs allocated (%.1f%%)", analysis.AllocatedGPUs, analysis.TotalGPUs, analysis.UtilizationPct),
+		})
 	}
 
 	// Fragmented GPUs
@@ -147,14 +288,21 @@ func (a *Analyzer) generateRecommendations(analysis Analysis) []string {
 		}
 	}
 	if fragmentedNodes > len(analysis.Nodes)/2 {
-		recommendations = append(recommendations,
-			"Many nodes have partially allocated GPUs. Consider using node affinity to pack GPU workloads efficiently.")
+		recommendations = append(recommendations, GPURecommendation{
+			Type:     Fragmentation,
+			Severity: "Medium",
+			Message:  "Many nodes have partially allocated GPUs. Consider using node affinity to pack GPU workloads efficiently.",
+			Details:  fmt.Sprintf("%d of %d GPU nodes partially allocated", fragmentedNodes, len(analysis.Nodes)),
+		})
 	}
 
 	// No GPUs but could use them
 	if analysis.TotalGPUs == 0 {
-		recommendations = append(recommendations,
-			"No GPU resources detected. If you have ML/AI workloads, consider adding GPU nodes for better performance.")
+		recommendations = append(recommendations, GPURecommendation{
+			Type:     NoGPU,
+			Severity: "Low",
+			Message:  "No GPU resources detected. If you have ML/AI workloads, consider adding GPU nodes for better performance.",
+		})
 	}
 
 	// Single GPU pods that could share
@@ -165,8 +313,12 @@ func (a *Analyzer) generateRecommendations(analysis Analysis) []string {
 		}
 	}
 	if singleGPUPods > 2 {
-		recommendations = append(recommendations,
-			"Multiple pods requesting single GPUs. Consider MIG (Multi-Instance GPU) or time-slicing for better utilization.")
+		recommendations = append(recommendations, GPURecommendation{
+			Type:     ConsiderMIG,
+			Severity: "Medium",
+			Message:  "Multiple pods requesting single GPUs. Consider MIG (Multi-Instance GPU) or time-slicing for better utilization.",
+			Details:  fmt.Sprintf("%d pods request exactly one GPU", singleGPUPods),
+		})
 	}
 
 	return recommendations
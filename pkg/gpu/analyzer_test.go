@@ -0,0 +1,133 @@
+package gpu
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func gpuNode(name string, gpus string) corev1.Node {
+	q := resource.MustParse(gpus)
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Capacity:    corev1.ResourceList{"nvidia.com/gpu": q},
+			Allocatable: corev1.ResourceList{"nvidia.com/gpu": q},
+		},
+	}
+}
+
+func gpuPod(name, namespace, node, gpus string) corev1.Pod {
+	q := resource.MustParse(gpus)
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name: "c",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{"nvidia.com/gpu": q},
+					Limits:   corev1.ResourceList{"nvidia.com/gpu": q},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestAnalyzeFractionalGPU(t *testing.T) {
+	analysis := NewAnalyzer().Analyze(
+		[]corev1.Node{gpuNode("node-1", "1")},
+		[]corev1.Pod{gpuPod("shared", "default", "node-1", "500m")},
+	)
+
+	if len(analysis.Pods) != 1 {
+		t.Fatalf("got %d GPU pods, want 1", len(analysis.Pods))
+	}
+	if got := analysis.Pods[0].GPUCount; got != 0.5 {
+		t.Errorf("pod GPUCount = %g, want 0.5", got)
+	}
+	if got := analysis.Nodes[0].RequestedGPUs; got != 0.5 {
+		t.Errorf("node RequestedGPUs = %g, want 0.5", got)
+	}
+}
+
+func TestAnalyzeCountsOtherNamespaces(t *testing.T) {
+	nodes := []corev1.Node{gpuNode("gpu-1", "4")}
+	pods := []corev1.Pod{
+		gpuPod("train", "ml", "gpu-1", "2"),
+		gpuPod("infer", "serving", "gpu-1", "2"),
+	}
+
+	analysis := NewAnalyzer().Analyze(nodes, pods)
+	analysis.FilterNamespace("ml")
+
+	if analysis.AvailableGPUs != 0 || analysis.AllocatedGPUs != 4 {
+		t.Errorf("allocated/available = %d/%d, want 4/0", analysis.AllocatedGPUs, analysis.AvailableGPUs)
+	}
+	for _, rec := range analysis.Recommendations {
+		if rec.Type == LowUtilization {
+			t.Errorf("unexpected %s recommendation on a fully used node", rec.Type)
+		}
+	}
+	if len(analysis.Pods) != 1 || analysis.Pods[0].PodName != "train" {
+		t.Errorf("pods = %+v, want only ml/train", analysis.Pods)
+	}
+}
+
+func TestThresholdsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       Thresholds
+		wantErr bool
+	}{
+		{"low -1", Thresholds{LowUtilizationPct: -1, HighUtilizationPct: 85}, true},
+		{"low 0", Thresholds{LowUtilizationPct: 0, HighUtilizationPct: 85}, false},
+		{"high 100", Thresholds{LowUtilizationPct: 50, HighUtilizationPct: 100}, false},
+		{"high 101", Thresholds{LowUtilizationPct: 50, HighUtilizationPct: 101}, true},
+		{"low 100 high 100", Thresholds{LowUtilizationPct: 100, HighUtilizationPct: 100}, false},
+		{"low 101", Thresholds{LowUtilizationPct: 101, HighUtilizationPct: 100}, true},
+		{"low above high", Thresholds{LowUtilizationPct: 90, HighUtilizationPct: 80}, true},
+		{"low equals high", Thresholds{LowUtilizationPct: 60, HighUtilizationPct: 60}, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.t.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUtilizationThresholdBoundaries(t *testing.T) {
+	tests := []struct {
+		name      string
+		allocated string
+		want      RecommendationType
+	}{
+		{"below low", "9", LowUtilization},
+		{"at low", "10", ""},
+		{"at high", "17", ""},
+		{"above high", "18", HighUtilization},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 20 GPUs, so each GPU is 5% against the default 50/85 thresholds
+			nodes := []corev1.Node{gpuNode("gpu-1", "20")}
+			pods := []corev1.Pod{gpuPod("train", "ml", "gpu-1", tt.allocated)}
+			analysis := NewAnalyzer().Analyze(nodes, pods)
+
+			var got RecommendationType
+			for _, rec := range analysis.Recommendations {
+				if rec.Type == LowUtilization || rec.Type == HighUtilization {
+					got = rec.Type
+				}
+			}
+			if got != tt.want {
+				t.Errorf("utilization %.0f%%: got recommendation %q, want %q", analysis.UtilizationPct, got, tt.want)
+			}
+		})
+	}
+}
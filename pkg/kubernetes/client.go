@@ -2,9 +2,10 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,9 +20,41 @@ type Client struct {
 	config    *rest.Config
 }
 
+// ErrNoKubeConfig is returned (wrapped in a *KubeConfigError) when no
+// in-cluster config or kubeconfig file could be found
+var ErrNoKubeConfig = errors.New("no kubernetes configuration found")
+
+// KubeConfigError explains which config locations were tried and how to fix it
+type KubeConfigError struct {
+	Tried []string // Locations tried, in resolution order
+	Err   error    // Underlying error from the last attempt
+}
+
+func (e *KubeConfigError) Error() string {
+	var b strings.Builder
+	b.WriteString(ErrNoKubeConfig.Error())
+	b.WriteString(". Tried, in order:\n")
+	for i, location := range e.Tried {
+		fmt.Fprintf(&b, "  %d. %s\n", i+1, location)
+	}
+	b.WriteString("Point kcavo at a cluster with --kubeconfig <path> or by setting $KUBECONFIG.")
+	return b.String()
+}
+
+// Unwrap lets errors.Is match both ErrNoKubeConfig and the underlying error
+func (e *KubeConfigError) Unwrap() []error {
+	return []error{ErrNoKubeConfig, e.Err}
+}
+
 // NewClient creates a new Kubernetes client
 func NewClient() (*Client, error) {
-	config, err := getConfig()
+	return NewClientWithKubeconfig("")
+}
+
+// NewClientWithKubeconfig creates a client from an explicit kubeconfig path.
+// An empty path uses the default resolution order.
+func NewClientWithKubeconfig(kubeconfig string) (*Client, error) {
+	config, err := getConfig(kubeconfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
 	}
@@ -37,26 +70,61 @@ func NewClient() (*Client, error) {
 	}, nil
 }
 
-// getConfig returns the Kubernetes config
-func getConfig() (*rest.Config, error) {
+// getConfig returns the Kubernetes config. An explicit kubeconfig path wins;
+// otherwise in-cluster config, $KUBECONFIG and ~/.kube/config are tried in order.
+// $KUBECONFIG may list several files, which are merged the way kubectl does.
+func getConfig(explicit string) (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if explicit != "" {
+		rules.ExplicitPath = explicit
+		return buildConfig(rules, []string{explicit}, []string{"--kubeconfig " + explicit})
+	}
+
 	// Try in-cluster config first
 	config, err := rest.InClusterConfig()
 	if err == nil {
 		return config, nil
 	}
+	tried := []string{"in-cluster service account (not running inside a pod)"}
 
 	// Fall back to kubeconfig
-	kubeconfig := os.Getenv("KUBECONFIG")
-	if kubeconfig == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		tried = append(tried, "$KUBECONFIG="+env)
+	} else {
+		tried = append(tried, "$KUBECONFIG (not set)", clientcmd.RecommendedHomeFile)
+	}
+
+	return buildConfig(rules, rules.Precedence, tried)
+}
+
+// buildConfig loads kubeconfig files with the given loading rules, reporting
+// a missing or empty config as a *KubeConfigError. Missing files in a list
+// are skipped; the error is only raised when none of them exist.
+func buildConfig(rules *clientcmd.ClientConfigLoadingRules, paths []string, tried []string) (*rest.Config, error) {
+	var notFound error = os.ErrNotExist
+	found := false
+	for _, path := range paths {
+		_, err := os.Stat(path)
+		if err == nil {
+			found = true
+			break
+		}
+		if !os.IsNotExist(err) {
 			return nil, err
 		}
-		kubeconfig = filepath.Join(home, ".kube", "config")
+		notFound = err
+	}
+	if !found {
+		tried[len(tried)-1] += " (not found)"
+		return nil, &KubeConfigError{Tried: tried, Err: notFound}
 	}
 
-	config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
 	if err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			tried[len(tried)-1] += " (empty)"
+			return nil, &KubeConfigError{Tried: tried, Err: err}
+		}
 		return nil, fmt.Errorf("failed to build config from kubeconfig: %w", err)
 	}
 
@@ -110,3 +178,41 @@ func (c *Client) GetNamespaces(ctx context.Context) ([]corev1.Namespace, error)
 
 	return namespaceList.Items, nil
 }
+
+// GetServices returns services in the specified namespace
+func (c *Client) GetServices(ctx context.Context, namespace string) ([]corev1.Service, error) {
+	if namespace == "" {
+		namespace = metav1.NamespaceAll
+	}
+
+	serviceList, err := c.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return serviceList.Items, nil
+}
+
+// GetLimitRanges returns LimitRanges in the specified namespace
+func (c *Client) GetLimitRanges(ctx context.Context, namespace string) ([]corev1.LimitRange, error) {
+	if namespace == "" {
+		namespace = metav1.NamespaceAll
+	}
+
+	limitRangeList, err := c.clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return limitRangeList.Items, nil
+}
+
+// GetPVs returns all PersistentVolumes in the cluster
+func (c *Client) GetPVs(ctx context.Context) ([]corev1.PersistentVolume, error) {
+	pvList, err := c.clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return pvList.Items, nil
+}
@@ -0,0 +1,89 @@
+package kubernetes
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const clusterConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+current-context: dev
+`
+
+const userConfig = `apiVersion: v1
+kind: Config
+users:
+- name: dev
+  user:
+    token: secret
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGetConfigKubeconfigList(t *testing.T) {
+	dir := t.TempDir()
+	clusters := writeFile(t, dir, "clusters", clusterConfig)
+	users := writeFile(t, dir, "users", userConfig)
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name      string
+		env       []string
+		wantToken string
+		wantErr   bool
+	}{
+		{"single file", []string{clusters}, "", false},
+		{"list merged like kubectl", []string{clusters, users}, "secret", false},
+		{"missing entries skipped", []string{missing, clusters, users}, "secret", false},
+		{"nothing exists", []string{missing, filepath.Join(dir, "also-missing")}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Keep rest.InClusterConfig from succeeding when run inside a pod
+			t.Setenv("KUBERNETES_SERVICE_HOST", "")
+			t.Setenv("KUBERNETES_SERVICE_PORT", "")
+			t.Setenv("KUBECONFIG", strings.Join(tt.env, string(os.PathListSeparator)))
+
+			config, err := getConfig("")
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoKubeConfig) {
+					t.Fatalf("getConfig error = %v, want ErrNoKubeConfig", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getConfig: %v", err)
+			}
+			if config.Host != "https://dev.example.com" || config.BearerToken != tt.wantToken {
+				t.Errorf("config host/token = %q/%q, want https://dev.example.com/%q", config.Host, config.BearerToken, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestGetConfigExplicitMissing(t *testing.T) {
+	_, err := getConfig(filepath.Join(t.TempDir(), "missing"))
+	if !errors.Is(err, ErrNoKubeConfig) {
+		t.Errorf("getConfig error = %v, want ErrNoKubeConfig", err)
+	}
+}
@@ -0,0 +1,139 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ControllerRef identifies a top-level workload controller
+type ControllerRef struct {
+	Kind string // Deployment, StatefulSet, DaemonSet, ReplicaSet or Job
+	Name string
+}
+
+func (r ControllerRef) String() string {
+	return strings.ToLower(r.Kind) + "/" + r.Name
+}
+
+// controllerKinds maps accepted (kubectl-style) kind names to API kinds
+var controllerKinds = map[string]string{
+	"deployment":  "Deployment",
+	"deploy":      "Deployment",
+	"statefulset": "StatefulSet",
+	"sts":         "StatefulSet",
+	"daemonset":   "DaemonSet",
+	"ds":          "DaemonSet",
+	"replicaset":  "ReplicaSet",
+	"rs":          "ReplicaSet",
+	"job":         "Job",
+}
+
+// ParseControllerRef parses a "kind/name" reference such as "deployment/api"
+func ParseControllerRef(s string) (ControllerRef, error) {
+	kind, name, ok := strings.Cut(s, "/")
+	if !ok || name == "" {
+		return ControllerRef{}, fmt.Errorf("invalid controller %q (expected kind/name, e.g. deployment/api)", s)
+	}
+
+	apiKind, ok := controllerKinds[strings.ToLower(kind)]
+	if !ok {
+		return ControllerRef{}, fmt.Errorf("unsupported controller kind %q (supported: deployment, statefulset, daemonset, replicaset, job)", kind)
+	}
+
+	return ControllerRef{Kind: apiKind, Name: name}, nil
+}
+
+// CheckController returns an error if the referenced controller does not exist
+func (c *Client) CheckController(ctx context.Context, namespace string, ref ControllerRef) error {
+	var err error
+	switch ref.Kind {
+	case "Deployment":
+		_, err = c.clientset.AppsV1().Deployments(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "StatefulSet":
+		_, err = c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "DaemonSet":
+		_, err = c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "ReplicaSet":
+		_, err = c.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	case "Job":
+		_, err = c.clientset.BatchV1().Jobs(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	default:
+		return fmt.Errorf("unsupported controller kind %q", ref.Kind)
+	}
+
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%s not found in namespace %s", ref, namespace)
+	}
+	return err
+}
+
+// GetReplicaSets returns ReplicaSets in the specified namespace
+func (c *Client) GetReplicaSets(ctx context.Context, namespace string) ([]appsv1.ReplicaSet, error) {
+	if namespace == "" {
+		namespace = metav1.NamespaceAll
+	}
+
+	rsList, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return rsList.Items, nil
+}
+
+// ResolveController returns the top-level controller of a pod, following
+// ReplicaSet ownership up to its Deployment. The second return value is
+// false for pods without a controller.
+func ResolveController(pod corev1.Pod, replicaSets []appsv1.ReplicaSet) (ControllerRef, bool) {
+	chain := controllerChain(pod, replicaSets)
+	if len(chain) == 0 {
+		return ControllerRef{}, false
+	}
+	return chain[len(chain)-1], true
+}
+
+// controllerChain returns a pod's controllers from its direct owner up to
+// the top-level one, e.g. [ReplicaSet, Deployment]
+func controllerChain(pod corev1.Pod, replicaSets []appsv1.ReplicaSet) []ControllerRef {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return nil
+	}
+
+	chain := []ControllerRef{{Kind: owner.Kind, Name: owner.Name}}
+	if owner.Kind != "ReplicaSet" {
+		return chain
+	}
+
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		if rs.Namespace != pod.Namespace || rs.Name != owner.Name {
+			continue
+		}
+		if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil {
+			chain = append(chain, ControllerRef{Kind: rsOwner.Kind, Name: rsOwner.Name})
+		}
+		break
+	}
+
+	return chain
+}
+
+// FilterPodsByController returns the pods controlled by ref at any level of
+// their owner chain, so both deployment/api and its rs/api-<hash> match
+func FilterPodsByController(pods []corev1.Pod, replicaSets []appsv1.ReplicaSet, ref ControllerRef) []corev1.Pod {
+	filtered := make([]corev1.Pod, 0)
+	for _, pod := range pods {
+		if slices.Contains(controllerChain(pod, replicaSets), ref) {
+			filtered = append(filtered, pod)
+		}
+	}
+	return filtered
+}
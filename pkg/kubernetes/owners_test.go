@@ -0,0 +1,71 @@
+package kubernetes
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ownedBy(kind, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+}
+
+func TestFilterPodsByController(t *testing.T) {
+	replicaSets := []appsv1.ReplicaSet{
+		{ObjectMeta: metav1.ObjectMeta{Name: "api-7d9f", Namespace: "default", OwnerReferences: ownedBy("Deployment", "api")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "bare", Namespace: "default"}},
+	}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "api-7d9f-x", Namespace: "default", OwnerReferences: ownedBy("ReplicaSet", "api-7d9f")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "bare-y", Namespace: "default", OwnerReferences: ownedBy("ReplicaSet", "bare")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default", OwnerReferences: ownedBy("StatefulSet", "db")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "default"}},
+	}
+
+	tests := []struct {
+		ref  string
+		want []string
+	}{
+		{"deployment/api", []string{"api-7d9f-x"}},
+		{"rs/api-7d9f", []string{"api-7d9f-x"}},
+		{"rs/bare", []string{"bare-y"}},
+		{"sts/db", []string{"db-0"}},
+		{"deployment/bare", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			ref, err := ParseControllerRef(tt.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, pod := range FilterPodsByController(pods, replicaSets, ref) {
+				got = append(got, pod.Name)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("FilterPodsByController(%s) = %v, want %v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveController(t *testing.T) {
+	replicaSets := []appsv1.ReplicaSet{
+		{ObjectMeta: metav1.ObjectMeta{Name: "api-7d9f", Namespace: "default", OwnerReferences: ownedBy("Deployment", "api")}},
+	}
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-7d9f-x", Namespace: "default", OwnerReferences: ownedBy("ReplicaSet", "api-7d9f")}}
+
+	got, ok := ResolveController(pod, replicaSets)
+	if want := (ControllerRef{Kind: "Deployment", Name: "api"}); !ok || got != want {
+		t.Errorf("ResolveController = %v, %v, want %v, true", got, ok, want)
+	}
+
+	if _, ok := ResolveController(corev1.Pod{}, replicaSets); ok {
+		t.Error("ResolveController reported a controller for an unowned pod")
+	}
+}
@@ -16,31 +16,110 @@ type Recommendation struct {
 	Category    string // Rightsizing, Unused, GPU, Spot, etc.
 }
 
+// Report bundles recommendations with their savings totals for machine output
+type Report struct {
+	Recommendations   []Recommendation
+	SavingsByCategory map[string]float64
+	TotalSavings      float64
+}
+
+// NewReport builds a Report from a list of recommendations
+func NewReport(recs []Recommendation) Report {
+	report := Report{
+		Recommendations:   recs,
+		SavingsByCategory: SummarizeSavings(recs),
+	}
+	for _, rec := range recs {
+		report.TotalSavings += rec.Savings
+	}
+	return report
+}
+
+// SummarizeSavings totals potential savings per recommendation category
+func SummarizeSavings(recs []Recommendation) map[string]float64 {
+	byCategory := make(map[string]float64)
+	for _, rec := range recs {
+		byCategory[rec.Category] += rec.Savings
+	}
+	return byCategory
+}
+
+// Thresholds tunes the sensitivity of the optimizer. Values are percentages.
+type Thresholds struct {
+	// UnderutilizedPct flags a node when its allocatable CPU exceeds this
+	// share of capacity
+	UnderutilizedPct float64
+	// NodeSavingsPct is the share of an underutilized node's cost assumed
+	// recoverable by downsizing or removing it
+	NodeSavingsPct float64
+}
+
+// DefaultThresholds returns the built-in optimizer thresholds
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		UnderutilizedPct: 80,
+		NodeSavingsPct:   50,
+	}
+}
+
+// Validate checks that every threshold is a percentage within 0–100
+func (t Thresholds) Validate() error {
+	if t.UnderutilizedPct < 0 || t.UnderutilizedPct > 100 {
+		return fmt.Errorf("underutilized threshold must be within 0-100, got %g", t.UnderutilizedPct)
+	}
+	if t.NodeSavingsPct < 0 || t.NodeSavingsPct > 100 {
+		return fmt.Errorf("node savings estimate must be within 0-100, got %g", t.NodeSavingsPct)
+	}
+	return nil
+}
+
 // Optimizer generates cost optimization recommendations
 type Optimizer struct {
-	pricing *cost.Pricing
+	pricing    *cost.Pricing
+	thresholds Thresholds
 }
 
 // NewOptimizer creates a new optimizer
 func NewOptimizer() *Optimizer {
 	return &Optimizer{
-		pricing: cost.DefaultPricing(),
+		pricing:    cost.DefaultPricing(),
+		thresholds: DefaultThresholds(),
+	}
+}
+
+// NewOptimizerWithPricing creates an optimizer with custom pricing
+func NewOptimizerWithPricing(pricing *cost.Pricing) *Optimizer {
+	return &Optimizer{
+		pricing:    pricing,
+		thresholds: DefaultThresholds(),
+	}
+}
+
+// NewOptimizerWithThresholds creates an optimizer with custom pricing and
+// thresholds. Thresholds should be checked with Validate beforehand.
+func NewOptimizerWithThresholds(pricing *cost.Pricing, thresholds Thresholds) *Optimizer {
+	return &Optimizer{
+		pricing:    pricing,
+		thresholds: thresholds,
 	}
 }
 
 // Analyze generates optimization recommendations
-func (o *Optimizer) Analyze(pods []corev1.Pod, nodes []corev1.Node, costs []cost.PodCost) []Recommendation {
+func (o *Optimizer) Analyze(pods []corev1.Pod, nodes []corev1.Node, costs []cost.PodCost, limitRanges []corev1.LimitRange, pvs []corev1.PersistentVolume) []Recommendation {
 	recommendations := make([]Recommendation, 0)
 
 	// Check for over-provisioned pods
 	recommendations = append(recommendations, o.findOverProvisionedPods(pods, costs)...)
 
 	// Check for pods without resource requests
-	recommendations = append(recommendations, o.findPodsWithoutRequests(pods)...)
+	recommendations = append(recommendations, o.findPodsWithoutRequests(pods, limitRanges)...)
 
 	// Check for unused resources
 	recommendations = append(recommendations, o.findUnusedResources(nodes)...)
 
+	// Check for volumes no claim is using
+	recommendations = append(recommendations, o.findOrphanedVolumes(pvs)...)
+
 	// Check for expensive GPU usage
 	recommendations = append(recommendations, o.findExpensiveGPUUsage(pods, costs)...)
 
@@ -87,9 +166,11 @@ func (o *Optimizer) findOverProvisionedPods(pods []corev1.Pod, costs []cost.PodC
 	return recommendations
 }
 
-func (o *Optimizer) findPodsWithoutRequests(pods []corev1.Pod) []Recommendation {
+func (o *Optimizer) findPodsWithoutRequests(pods []corev1.Pod, limitRanges []corev1.LimitRange) []Recommendation {
 	recommendations := make([]Recommendation, 0)
-	count := 0
+	defaults := limitRangeDefaults(limitRanges)
+	missing := 0
+	defaulted := 0
 
 	for _, pod := range pods {
 		if pod.Status.Phase != corev1.PodRunning {
@@ -105,23 +186,84 @@ func (o *Optimizer) findPodsWithoutRequests(pods []corev1.Pod) []Recommendation
 		}
 
 		if !hasRequests {
-			count++
+			missing++
+			continue
+		}
+
+		// Requests identical to the namespace LimitRange defaults were most
+		// likely injected at admission rather than set by the user
+		if nsDefaults, ok := defaults[pod.Namespace]; ok && usesDefaultRequests(pod, nsDefaults) {
+			defaulted++
 		}
 	}
 
-	if count > 0 {
+	if missing > 0 {
 		recommendations = append(recommendations, Recommendation{
 			Title:       "Add resource requests to pods without them",
-			Description: fmt.Sprintf("%d pods don't have resource requests. This can lead to poor scheduling and cost visibility.", count),
+			Description: fmt.Sprintf("%d pods don't have resource requests and no LimitRange default applies. This can lead to poor scheduling and cost visibility.", missing),
 			Savings:     0, // Hard to estimate without knowing actual usage
 			Priority:    "Medium",
 			Category:    "Best Practice",
 		})
 	}
 
+	if defaulted > 0 {
+		recommendations = append(recommendations, Recommendation{
+			Title:       "Set explicit resource requests on pods relying on LimitRange defaults",
+			Description: fmt.Sprintf("%d pods use requests injected by a namespace LimitRange. Costs are based on those defaults, which may not match actual needs.", defaulted),
+			Savings:     0,
+			Priority:    "Low",
+			Category:    "Best Practice",
+		})
+	}
+
 	return recommendations
 }
 
+// limitRangeDefaults returns the default container requests per namespace.
+// Kubernetes falls back to the default limit when no default request is set.
+func limitRangeDefaults(limitRanges []corev1.LimitRange) map[string]corev1.ResourceList {
+	defaults := make(map[string]corev1.ResourceList)
+
+	for _, lr := range limitRanges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+
+			nsDefaults, ok := defaults[lr.Namespace]
+			if !ok {
+				nsDefaults = corev1.ResourceList{}
+			}
+			for name, q := range item.Default {
+				nsDefaults[name] = q
+			}
+			for name, q := range item.DefaultRequest {
+				nsDefaults[name] = q
+			}
+			if len(nsDefaults) > 0 {
+				defaults[lr.Namespace] = nsDefaults
+			}
+		}
+	}
+
+	return defaults
+}
+
+// usesDefaultRequests reports whether every container request in the pod
+// matches the LimitRange default for that resource
+func usesDefaultRequests(pod corev1.Pod, defaults corev1.ResourceList) bool {
+	for _, container := range pod.Spec.Containers {
+		for name, req := range container.Resources.Requests {
+			def, ok := defaults[name]
+			if !ok || req.Cmp(def) != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func (o *Optimizer) findUnusedResources(nodes []corev1.Node) []Recommendation {
 	recommendations := make([]Recommendation, 0)
 
@@ -135,10 +277,10 @@ func (o *Optimizer) findUnusedResources(nodes []corev1.Node) []Recommendation {
 		cpuCapacity := capacity[corev1.ResourceCPU]
 
 		// Simplified check - if allocatable is close to capacity, node might be underutilized
-		if cpuAllocatable.AsApproximateFloat64() > cpuCapacity.AsApproximateFloat64()*0.8 {
+		if cpuAllocatable.AsApproximateFloat64() > cpuCapacity.AsApproximateFloat64()*(o.thresholds.UnderutilizedPct/100) {
 			// This is a simplified estimation
 			nodeCost := o.estimateNodeCost(node)
-			savings := nodeCost * 0.5 // Estimate 50% savings if node can be removed/downsized
+			savings := nodeCost * (o.thresholds.NodeSavingsPct / 100) // Savings if node can be removed/downsized
 
 			recommendations = append(recommendations, Recommendation{
 				Title:       "Consider downsizing or removing underutilized node: " + node.Name,
@@ -153,6 +295,39 @@ func (o *Optimizer) findUnusedResources(nodes []corev1.Node) []Recommendation {
 	return recommendations
 }
 
+func (o *Optimizer) findOrphanedVolumes(pvs []corev1.PersistentVolume) []Recommendation {
+	recommendations := make([]Recommendation, 0)
+
+	for _, pv := range pvs {
+		// Released and Available volumes are not bound to any PVC but
+		// their backing storage is still billed
+		if pv.Status.Phase != corev1.VolumeReleased && pv.Status.Phase != corev1.VolumeAvailable {
+			continue
+		}
+
+		size := pv.Spec.Capacity[corev1.ResourceStorage]
+		policy := pv.Spec.PersistentVolumeReclaimPolicy
+
+		description := fmt.Sprintf("This %s volume (%s, reclaim policy %s) is not bound to any PVC but still incurs storage cost.",
+			pv.Status.Phase, size.String(), policy)
+		priority := "Medium"
+		if policy == corev1.PersistentVolumeReclaimRetain {
+			description += " Retain volumes are never cleaned up automatically; delete the PV and its backing disk manually."
+			priority = "High"
+		}
+
+		recommendations = append(recommendations, Recommendation{
+			Title:       "Remove orphaned volume: " + pv.Name,
+			Description: description,
+			Savings:     o.pricing.CalculateStorageCost(size.Value()),
+			Priority:    priority,
+			Category:    "Unused",
+		})
+	}
+
+	return recommendations
+}
+
 func (o *Optimizer) findExpensiveGPUUsage(pods []corev1.Pod, costs []cost.PodCost) []Recommendation {
 	recommendations := make([]Recommendation, 0)
 
@@ -161,11 +336,9 @@ func (o *Optimizer) findExpensiveGPUUsage(pods []corev1.Pod, costs []cost.PodCos
 			continue
 		}
 
-		gpuCount := 0
+		gpuCount := 0.0
 		for _, container := range pod.Spec.Containers {
-			if gpu, ok := container.Resources.Requests["nvidia.com/gpu"]; ok {
-				gpuCount += int(gpu.Value())
-			}
+			gpuCount += cost.ContainerGPUs(container)
 		}
 
 		if gpuCount > 0 && i < len(costs) {
@@ -190,7 +363,7 @@ func (o *Optimizer) estimateNodeCost(node corev1.Node) float64 {
 	mem := node.Status.Capacity[corev1.ResourceMemory]
 
 	cpuCost := o.pricing.CalculateCPUCost(cpu.AsApproximateFloat64())
-	memCost := o.pricing.CalculateMemoryCost(mem.Value())
+	memCost := o.pricing.CalculateMemoryCost(mem.AsApproximateFloat64())
 
 	return cpuCost + memCost
 }
@@ -0,0 +1,57 @@
+package optimize
+
+import (
+	"testing"
+
+	"kcavo/pkg/cost"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestThresholdsValidate(t *testing.T) {
+	tests := []struct {
+		pct     float64
+		wantErr bool
+	}{
+		{-1, true},
+		{0, false},
+		{100, false},
+		{101, true},
+	}
+
+	for _, tt := range tests {
+		utilization := Thresholds{UnderutilizedPct: tt.pct, NodeSavingsPct: 50}
+		if err := utilization.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("UnderutilizedPct %g: Validate() = %v, wantErr %v", tt.pct, err, tt.wantErr)
+		}
+
+		savings := Thresholds{UnderutilizedPct: 80, NodeSavingsPct: tt.pct}
+		if err := savings.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("NodeSavingsPct %g: Validate() = %v, wantErr %v", tt.pct, err, tt.wantErr)
+		}
+	}
+}
+
+func cpuNode(name, capacity, allocatable string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Capacity:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(capacity)},
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(allocatable)},
+		},
+	}
+}
+
+func TestFindUnusedResourcesThreshold(t *testing.T) {
+	o := NewOptimizerWithThresholds(cost.DefaultPricing(), Thresholds{UnderutilizedPct: 80, NodeSavingsPct: 50})
+
+	// Exactly at the threshold is not flagged; only strictly above is
+	if recs := o.findUnusedResources([]corev1.Node{cpuNode("at", "10", "8")}); len(recs) != 0 {
+		t.Errorf("node at 80%% threshold got %d recommendations, want 0", len(recs))
+	}
+	if recs := o.findUnusedResources([]corev1.Node{cpuNode("above", "10", "9")}); len(recs) != 1 {
+		t.Errorf("node above 80%% threshold got %d recommendations, want 1", len(recs))
+	}
+}
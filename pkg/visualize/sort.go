@@ -0,0 +1,185 @@
+package visualize
+
+import (
+	"sort"
+
+	"kcavo/pkg/cost"
+	"kcavo/pkg/gpu"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Sort keys accepted by --sort-by. Numeric keys sort descending and
+// name keys ascending; reverse flips either order.
+const (
+	SortByCost      = "cost"
+	SortByCPU       = "cpu"
+	SortByMemory    = "memory"
+	SortByGPU       = "gpu"
+	SortByName      = "name"
+	SortByNamespace = "namespace"
+)
+
+// sortRows stably sorts x with less, flipping the order when reverse is set
+func sortRows(x interface{}, less func(i, j int) bool, reverse bool) {
+	if reverse {
+		sort.SliceStable(x, func(i, j int) bool { return less(j, i) })
+		return
+	}
+	sort.SliceStable(x, less)
+}
+
+// SortPodCosts sorts pod costs by key. It returns the key actually used and
+// false when the requested key was not valid and cost was used instead.
+func SortPodCosts(costs []cost.PodCost, key string, reverse bool) (string, bool) {
+	var less func(i, j int) bool
+	switch key {
+	case SortByCost:
+		less = func(i, j int) bool { return costs[i].TotalCost > costs[j].TotalCost }
+	case SortByCPU:
+		less = func(i, j int) bool { return costs[i].CPUCost > costs[j].CPUCost }
+	case SortByMemory:
+		less = func(i, j int) bool { return costs[i].MemoryCost > costs[j].MemoryCost }
+	case SortByGPU:
+		less = func(i, j int) bool { return costs[i].GPUCount > costs[j].GPUCount }
+	case SortByName:
+		less = func(i, j int) bool {
+			if costs[i].Name != costs[j].Name {
+				return costs[i].Name < costs[j].Name
+			}
+			return costs[i].Namespace < costs[j].Namespace
+		}
+	case SortByNamespace:
+		less = func(i, j int) bool {
+			if costs[i].Namespace != costs[j].Namespace {
+				return costs[i].Namespace < costs[j].Namespace
+			}
+			return costs[i].Name < costs[j].Name
+		}
+	default:
+		SortPodCosts(costs, SortByCost, reverse)
+		return SortByCost, false
+	}
+
+	sortRows(costs, less, reverse)
+	return key, true
+}
+
+// SortNodeCosts sorts per-node costs by cost (workload cost) or name
+func SortNodeCosts(nodeCosts []cost.NodeCost, key string, reverse bool) (string, bool) {
+	var less func(i, j int) bool
+	switch key {
+	case SortByCost:
+		less = func(i, j int) bool { return nodeCosts[i].WorkloadCost > nodeCosts[j].WorkloadCost }
+	case SortByName:
+		less = func(i, j int) bool { return nodeCosts[i].Node < nodeCosts[j].Node }
+	default:
+		SortNodeCosts(nodeCosts, SortByCost, reverse)
+		return SortByCost, false
+	}
+
+	sortRows(nodeCosts, less, reverse)
+	return key, true
+}
+
+// SortGPUAnalysis sorts GPU nodes and pods by GPU count, name or (pods
+// only) namespace. Cost is accepted as an alias for GPU count.
+func SortGPUAnalysis(analysis *gpu.Analysis, key string, reverse bool) (string, bool) {
+	nodes, pods := analysis.Nodes, analysis.Pods
+
+	var nodeLess, podLess func(i, j int) bool
+	switch key {
+	case SortByGPU, SortByCost:
+		nodeLess = func(i, j int) bool { return nodes[i].AllocatedGPUs > nodes[j].AllocatedGPUs }
+		podLess = func(i, j int) bool { return pods[i].GPUCount > pods[j].GPUCount }
+	case SortByName:
+		nodeLess = func(i, j int) bool { return nodes[i].NodeName < nodes[j].NodeName }
+		podLess = func(i, j int) bool { return pods[i].PodName < pods[j].PodName }
+	case SortByNamespace:
+		nodeLess = func(i, j int) bool { return nodes[i].NodeName < nodes[j].NodeName }
+		podLess = func(i, j int) bool {
+			if pods[i].Namespace != pods[j].Namespace {
+				return pods[i].Namespace < pods[j].Namespace
+			}
+			return pods[i].PodName < pods[j].PodName
+		}
+	default:
+		SortGPUAnalysis(analysis, SortByGPU, reverse)
+		return SortByGPU, false
+	}
+
+	sortRows(nodes, nodeLess, reverse)
+	sortRows(pods, podLess, reverse)
+	return key, true
+}
+
+// SortNodes sorts nodes by name or by CPU or memory capacity. Namespace is
+// accepted as an alias for name.
+func SortNodes(nodes []corev1.Node, key string, reverse bool) (string, bool) {
+	capacity := func(i int, name corev1.ResourceName) float64 {
+		q := nodes[i].Status.Capacity[name]
+		return q.AsApproximateFloat64()
+	}
+
+	var less func(i, j int) bool
+	switch key {
+	case SortByName, SortByNamespace:
+		less = func(i, j int) bool { return nodes[i].Name < nodes[j].Name }
+	case SortByCPU:
+		less = func(i, j int) bool { return capacity(i, corev1.ResourceCPU) > capacity(j, corev1.ResourceCPU) }
+	case SortByMemory:
+		less = func(i, j int) bool { return capacity(i, corev1.ResourceMemory) > capacity(j, corev1.ResourceMemory) }
+	default:
+		SortNodes(nodes, SortByName, reverse)
+		return SortByName, false
+	}
+
+	sortRows(nodes, less, reverse)
+	return key, true
+}
+
+// SortPods sorts pods by name, namespace, or total CPU or memory request
+func SortPods(pods []corev1.Pod, key string, reverse bool) (string, bool) {
+	var less func(i, j int) bool
+	switch key {
+	case SortByName:
+		less = func(i, j int) bool {
+			if pods[i].Name != pods[j].Name {
+				return pods[i].Name < pods[j].Name
+			}
+			return pods[i].Namespace < pods[j].Namespace
+		}
+	case SortByNamespace:
+		less = func(i, j int) bool {
+			if pods[i].Namespace != pods[j].Namespace {
+				return pods[i].Namespace < pods[j].Namespace
+			}
+			return pods[i].Name < pods[j].Name
+		}
+	case SortByCPU:
+		less = func(i, j int) bool {
+			return podRequest(pods[i], corev1.ResourceCPU) > podRequest(pods[j], corev1.ResourceCPU)
+		}
+	case SortByMemory:
+		less = func(i, j int) bool {
+			return podRequest(pods[i], corev1.ResourceMemory) > podRequest(pods[j], corev1.ResourceMemory)
+		}
+	default:
+		SortPods(pods, SortByName, reverse)
+		return SortByName, false
+	}
+
+	sortRows(pods, less, reverse)
+	return key, true
+}
+
+// podRequest sums a resource's requests across a pod's containers
+func podRequest(pod corev1.Pod, name corev1.ResourceName) float64 {
+	total := 0.0
+	for _, container := range pod.Spec.Containers {
+		if req, ok := container.Resources.Requests[name]; ok {
+			total += req.AsApproximateFloat64()
+		}
+	}
+	return total
+}
@@ -0,0 +1,112 @@
+package visualize
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testNode(name, cpu, memory string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{Capacity: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}},
+	}
+}
+
+func testPod(name, namespace, cpu, memory string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}}},
+	}
+}
+
+func TestSortNodes(t *testing.T) {
+	tests := []struct {
+		key     string
+		reverse bool
+		want    []string
+		ok      bool
+	}{
+		{SortByName, false, []string{"a", "b", "c"}, true},
+		{SortByCPU, false, []string{"b", "c", "a"}, true},
+		{SortByMemory, false, []string{"c", "a", "b"}, true},
+		{SortByMemory, true, []string{"b", "a", "c"}, true},
+		{SortByCost, false, []string{"a", "b", "c"}, false},
+	}
+
+	for _, tt := range tests {
+		nodes := []corev1.Node{
+			testNode("c", "4", "64Gi"),
+			testNode("a", "2", "16Gi"),
+			testNode("b", "8", "8Gi"),
+		}
+		_, ok := SortNodes(nodes, tt.key, tt.reverse)
+		if ok != tt.ok {
+			t.Errorf("SortNodes(%s) ok = %v, want %v", tt.key, ok, tt.ok)
+		}
+		for i, node := range nodes {
+			if node.Name != tt.want[i] {
+				t.Errorf("SortNodes(%s, reverse=%v) = %v, want %v", tt.key, tt.reverse, nodeNames(nodes), tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSortPods(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+		ok   bool
+	}{
+		{SortByName, []string{"api", "db", "web"}, true},
+		{SortByNamespace, []string{"db", "api", "web"}, true},
+		{SortByCPU, []string{"web", "api", "db"}, true},
+		{SortByMemory, []string{"db", "web", "api"}, true},
+		{SortByGPU, []string{"api", "db", "web"}, false},
+	}
+
+	for _, tt := range tests {
+		pods := []corev1.Pod{
+			testPod("web", "prod", "2", "1Gi"),
+			testPod("db", "data", "250m", "4Gi"),
+			testPod("api", "prod", "500m", "512Mi"),
+		}
+		_, ok := SortPods(pods, tt.key, false)
+		if ok != tt.ok {
+			t.Errorf("SortPods(%s) ok = %v, want %v", tt.key, ok, tt.ok)
+		}
+		for i, pod := range pods {
+			if pod.Name != tt.want[i] {
+				t.Errorf("SortPods(%s) = %v, want %v", tt.key, podNames(pods), tt.want)
+				break
+			}
+		}
+	}
+}
+
+func nodeNames(nodes []corev1.Node) []string {
+	names := make([]string, len(nodes))
+	for i, node := range nodes {
+		names[i] = node.Name
+	}
+	return names
+}
+
+func podNames(pods []corev1.Pod) []string {
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		names[i] = pod.Name
+	}
+	return names
+}
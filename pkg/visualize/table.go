@@ -11,17 +11,43 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // PrintCostTable prints costs in a formatted table
 func PrintCostTable(costs []cost.PodCost, showBreakdown bool) {
 	table := tablewriter.NewWriter(os.Stdout)
 
+	// Only show the extended resource and proration columns when used
+	showOther, showProrated := false, false
+	for _, c := range costs {
+		if len(c.ExtendedResources) > 0 {
+			showOther = true
+		}
+		if c.ProratedCost > 0 {
+			showProrated = true
+		}
+	}
+
+	totalHeader := "Total Cost"
+	if showProrated {
+		totalHeader = "Monthly Rate"
+	}
+
+	var header []string
 	if showBreakdown {
-		table.SetHeader([]string{"Pod", "Namespace", "Node", "CPU Cost", "Memory Cost", "GPU Cost", "Total Cost"})
+		header = []string{"Pod", "Namespace", "Node", "CPU Cost", "Memory Cost", "GPU Cost"}
+		if showOther {
+			header = append(header, "Other Cost")
+		}
+		header = append(header, totalHeader)
 	} else {
-		table.SetHeader([]string{"Pod", "Namespace", "Total Cost"})
+		header = []string{"Pod", "Namespace", totalHeader}
+	}
+	if showProrated {
+		header = append(header, "Age", "Prorated Actual")
 	}
+	table.SetHeader(header)
 
 	table.SetBorder(true)
 	table.SetRowLine(false)
@@ -38,23 +64,86 @@ func PrintCostTable(costs []cost.PodCost, showBreakdown bool) {
 	table.SetNoWhiteSpace(true)
 
 	for _, c := range costs {
+		var row []string
 		if showBreakdown {
-			table.Append([]string{
+			row = []string{
 				c.Name,
 				c.Namespace,
 				c.Node,
 				fmt.Sprintf("$%.2f", c.CPUCost),
 				fmt.Sprintf("$%.2f", c.MemoryCost),
 				fmt.Sprintf("$%.2f", c.GPUCost),
-				fmt.Sprintf("$%.2f", c.TotalCost),
-			})
+			}
+			if showOther {
+				row = append(row, fmt.Sprintf("$%.2f", c.OtherCost))
+			}
+			row = append(row, fmt.Sprintf("$%.2f", c.TotalCost))
 		} else {
-			table.Append([]string{
+			row = []string{
 				c.Name,
 				c.Namespace,
 				fmt.Sprintf("$%.2f/mo", c.TotalCost),
-			})
+			}
+		}
+		if showProrated {
+			row = append(row, formatAge(c.AgeHours), fmt.Sprintf("$%.2f", c.ProratedCost))
+		}
+		table.Append(row)
+	}
+
+	table.Render()
+}
+
+// PrintHighlights prints the most expensive namespace, node and pod
+func PrintHighlights(top cost.TopSpenders) {
+	if top.Namespace.Name == "" && top.Node.Name == "" && top.Pod.Name == "" {
+		return
+	}
+
+	fmt.Println("🔥 Highlights:")
+	if top.Namespace.Name != "" {
+		fmt.Printf("   Most expensive namespace: %s ($%.2f/mo)\n", top.Namespace.Name, top.Namespace.Cost)
+	}
+	if top.Node.Name != "" {
+		fmt.Printf("   Most expensive node:      %s ($%.2f/mo)\n", top.Node.Name, top.Node.Cost)
+	}
+	if top.Pod.Name != "" {
+		fmt.Printf("   Most expensive pod:       %s ($%.2f/mo)\n", top.Pod.Name, top.Pod.Cost)
+	}
+	fmt.Println()
+}
+
+// formatAge renders an age in hours like kubectl does (e.g. 5h, 3d)
+func formatAge(hours float64) string {
+	if hours < 48 {
+		return fmt.Sprintf("%.0fh", hours)
+	}
+	return fmt.Sprintf("%.0fd", hours/24)
+}
+
+// PrintNodeCostTable prints pod costs aggregated per node
+func PrintNodeCostTable(nodeCosts []cost.NodeCost) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node", "Pods", "Workload Cost", "Node Cost", "Packing Efficiency"})
+	table.SetBorder(false)
+	table.SetHeaderLine(true)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+
+	for _, nc := range nodeCosts {
+		nodeCost := "-"
+		efficiency := "-"
+		if nc.NodeCost > 0 {
+			nodeCost = fmt.Sprintf("$%.2f/mo", nc.NodeCost)
+			efficiency = fmt.Sprintf("%.1f%%", nc.PackingEfficiency)
 		}
+		table.Append([]string{
+			nc.Node,
+			fmt.Sprintf("%d", nc.PodCount),
+			fmt.Sprintf("$%.2f/mo", nc.WorkloadCost),
+			nodeCost,
+			efficiency,
+		})
 	}
 
 	table.Render()
@@ -70,7 +159,7 @@ func PrintGPUTable(analysis gpu.Analysis) {
 	}
 
 	nodeTable := tablewriter.NewWriter(os.Stdout)
-	nodeTable.SetHeader([]string{"Node", "GPU Type", "Total", "Allocated", "Available", "Utilization"})
+	nodeTable.SetHeader([]string{"Node", "GPU Type", "Total", "Requested", "Allocated", "Available", "Utilization"})
 	nodeTable.SetBorder(false)
 	nodeTable.SetHeaderLine(true)
 	nodeTable.SetTablePadding("\t")
@@ -85,6 +174,7 @@ func PrintGPUTable(analysis gpu.Analysis) {
 			node.NodeName,
 			node.GPUType,
 			fmt.Sprintf("%d", node.TotalGPUs),
+			fmt.Sprintf("%g", node.RequestedGPUs),
 			fmt.Sprintf("%d", node.AllocatedGPUs),
 			fmt.Sprintf("%d", node.AvailableGPUs),
 			fmt.Sprintf("%.1f%%", util),
@@ -112,7 +202,7 @@ func PrintGPUTable(analysis gpu.Analysis) {
 			pod.PodName,
 			pod.Namespace,
 			pod.Node,
-			fmt.Sprintf("%d", pod.GPUCount),
+			fmt.Sprintf("%g", pod.GPUCount),
 		})
 	}
 	podTable.Render()
@@ -175,24 +265,25 @@ func PrintPodTable(pods []corev1.Pod) {
 	table.SetNoWhiteSpace(true)
 
 	for _, pod := range pods {
-		// Sum up resources across containers
-		var cpuReq, memReq int64
+		// Sum up resources across containers. Quantity.Add uses big
+		// arithmetic internally, so huge memory totals cannot overflow.
+		var cpuReq, memReq resource.Quantity
 		for _, container := range pod.Spec.Containers {
 			if req, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
-				cpuReq += req.MilliValue()
+				cpuReq.Add(req)
 			}
 			if req, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
-				memReq += req.Value()
+				memReq.Add(req)
 			}
 		}
 
-		cpuStr := fmt.Sprintf("%dm", cpuReq)
-		if cpuReq == 0 {
+		cpuStr := fmt.Sprintf("%dm", cpuReq.MilliValue())
+		if cpuReq.IsZero() {
 			cpuStr = "-"
 		}
 
-		memStr := fmt.Sprintf("%dMi", memReq/(1024*1024))
-		if memReq == 0 {
+		memStr := fmt.Sprintf("%.0fMi", memReq.AsApproximateFloat64()/(1024*1024))
+		if memReq.IsZero() {
 			memStr = "-"
 		}
 
@@ -216,6 +307,18 @@ func PrintJSON(data interface{}) error {
 	return encoder.Encode(data)
 }
 
+// PrintJSONLines prints each row as a compact single-line JSON object (JSONL),
+// with the same field names as PrintJSON
+func PrintJSONLines[T any](rows []T) error {
+	encoder := json.NewEncoder(os.Stdout)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // PrintYAML prints data as YAML
 func PrintYAML(data interface{}) error {
 	encoder := yaml.NewEncoder(os.Stdout)